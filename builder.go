@@ -0,0 +1,82 @@
+package framestruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// FrameBuilder builds a *data.Frame one row at a time, for rows that come
+// from paginated APIs, database cursors, and other sources that can't
+// produce a whole slice up front. Rows are converted exactly like the
+// elements of a slice passed to ToDataFrame.
+type FrameBuilder struct {
+	name string
+	typ  reflect.Type
+	c    *converter
+}
+
+// NewFrameBuilder returns a FrameBuilder for rows of the same type as row,
+// which must be a struct, a map, or a value like a float64. row is only used
+// for its type, so it can be the first row or a nil pointer like
+// (*MyStruct)(nil).
+func NewFrameBuilder(name string, row interface{}, opts ...Option) (*FrameBuilder, error) {
+	t := reflect.TypeOf(row)
+	if t == nil {
+		return nil, errors.New("unsupported type: can only convert structs, maps, and values")
+	}
+
+	t = indirectType(t)
+	if !supportedRowType(t) {
+		return nil, fmt.Errorf("unsupported type %s: can only convert structs, maps, and values", t)
+	}
+
+	c := newConverter(opts...)
+	c.nameScalars(name)
+	if isNestedStruct(t) {
+		// report invalid tags before any rows are appended
+		if _, err := c.plan(t, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &FrameBuilder{
+		name: name,
+		typ:  t,
+		c:    c,
+	}, nil
+}
+
+// Append converts row and adds it to the frame. row must have the type the
+// builder was created with, or be a pointer to it. When row can't be
+// converted, Append returns an error and the frame is left unchanged. A nil
+// row is an error unless the builder has WithNullRows.
+func (b *FrameBuilder) Append(row interface{}) error {
+	v := indirect(reflect.ValueOf(row))
+	if !v.IsValid() {
+		if b.c.nullRows {
+			return b.c.appendNullRow()
+		}
+		return errors.New("row must not be nil")
+	}
+
+	if v.Type() != b.typ {
+		return fmt.Errorf("unsupported type %T: rows must be %s", row, b.typ)
+	}
+	return b.c.appendRow(v)
+}
+
+// Rows returns the number of rows in the frame so far
+func (b *FrameBuilder) Rows() int {
+	return b.c.rows
+}
+
+// Frame returns the frame built from the appended rows. It only returns an
+// error when the rows can't be made into a frame as a whole, like rows with
+// the same time with WithTimeSort(RejectDuplicateTimes). Rows shouldn't be
+// appended after calling Frame.
+func (b *FrameBuilder) Frame() (*data.Frame, error) {
+	return b.c.createFrame(b.name)
+}
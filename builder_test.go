@@ -0,0 +1,108 @@
+package framestruct_test
+
+import (
+	"testing"
+
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameBuilder(t *testing.T) {
+	t.Run("it builds the same frame as ToDataFrame", func(t *testing.T) {
+		strcts := []nested1{
+			{"foo", 36, "baz", nested3{true, 100}},
+			{"foo1", 37, "baz1", nested3{false, 101}},
+		}
+
+		builder, err := framestruct.NewFrameBuilder("results", strcts[0])
+		require.Nil(t, err)
+		for _, strct := range strcts {
+			require.Nil(t, builder.Append(strct))
+		}
+		require.Equal(t, 2, builder.Rows())
+
+		expected, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		frame, err := builder.Frame()
+		require.Nil(t, err)
+		require.Equal(t, expected, frame)
+	})
+
+	t.Run("it takes its type from a nil pointer", func(t *testing.T) {
+		builder, err := framestruct.NewFrameBuilder("results", (*simpleStruct)(nil))
+		require.Nil(t, err)
+
+		require.Nil(t, builder.Append(simpleStruct{"foo", 36, "baz"}))
+		require.Nil(t, builder.Append(&simpleStruct{"foo1", 37, "baz1"}))
+
+		frame, err := builder.Frame()
+		require.Nil(t, err)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, "foo1", frame.Fields[0].At(1))
+	})
+
+	t.Run("it builds frames from maps", func(t *testing.T) {
+		builder, err := framestruct.NewFrameBuilder("results", map[string]interface{}(nil))
+		require.Nil(t, err)
+
+		require.Nil(t, builder.Append(map[string]interface{}{"a": "foo"}))
+		require.Nil(t, builder.Append(map[string]interface{}{"b": int32(36)}))
+
+		frame, err := builder.Frame()
+		require.Nil(t, err)
+		rows, err := frame.RowLen()
+		require.Nil(t, err)
+		require.Equal(t, 2, rows)
+		require.Equal(t, []string{"a", "b"}, fieldNames(frame))
+	})
+
+	t.Run("it leaves the frame unchanged when a row can't be converted", func(t *testing.T) {
+		builder, err := framestruct.NewFrameBuilder("results", map[string]interface{}(nil))
+		require.Nil(t, err)
+
+		require.Nil(t, builder.Append(map[string]interface{}{"a": "foo"}))
+		require.Error(t, builder.Append(map[string]interface{}{"a": "foo1", "b": complex64(1)}))
+		require.Nil(t, builder.Append(map[string]interface{}{"a": "foo2"}))
+
+		frame, err := builder.Frame()
+		require.Nil(t, err)
+		require.Equal(t, []string{"a"}, fieldNames(frame))
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Equal(t, "foo2", frame.Fields[0].At(1))
+	})
+
+	t.Run("it returns an error for rows of another type", func(t *testing.T) {
+		builder, err := framestruct.NewFrameBuilder("results", simpleStruct{})
+		require.Nil(t, err)
+
+		err = builder.Append(nested3{})
+		require.Error(t, err)
+		require.Equal(t, "unsupported type framestruct_test.nested3: rows must be framestruct_test.simpleStruct", err.Error())
+
+		require.Error(t, builder.Append((*simpleStruct)(nil)))
+		require.Error(t, builder.Append(nil))
+	})
+
+	t.Run("it appends a null row for a nil row with WithNullRows", func(t *testing.T) {
+		builder, err := framestruct.NewFrameBuilder("results", (**simpleStruct)(nil), framestruct.WithNullRows())
+		require.Nil(t, err)
+
+		strct := &simpleStruct{"foo", 36, "baz"}
+		require.Nil(t, builder.Append(&strct))
+		require.Nil(t, builder.Append((*simpleStruct)(nil)))
+
+		frame, err := builder.Frame()
+		require.Nil(t, err)
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Nil(t, frame.Fields[0].At(1))
+	})
+
+	t.Run("it returns an error for unsupported row types", func(t *testing.T) {
+		_, err := framestruct.NewFrameBuilder("results", []string{"foo"})
+		require.Error(t, err)
+
+		_, err = framestruct.NewFrameBuilder("results", nil)
+		require.Error(t, err)
+	})
+}
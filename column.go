@@ -0,0 +1,93 @@
+package framestruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// column is a field under construction. Fields are preallocated to the
+// number of rows the converter expects, so len tracks how many of its
+// values have been written. Values past len are always zero, or null once
+// the field is nullable.
+type column struct {
+	field *data.Field
+	len   int
+}
+
+// newColumn returns a column of length n for values like v
+func newColumn(name string, v reflect.Value, n int) (*column, error) {
+	if fieldType(derefType(v.Type())) == nil {
+		return nil, fmt.Errorf("unsupported type %T", v.Interface())
+	}
+
+	f := data.NewFieldFromFieldType(fieldTypeFor(v), n)
+	f.Name = name
+	return &column{field: f}, nil
+}
+
+func (col *column) append(v reflect.Value) error {
+	ft := fieldTypeFor(v)
+	switch {
+	case ft == col.field.Type():
+		col.field.Set(col.next(), v.Interface())
+	case ft.NullableType() == col.field.Type():
+		col.field.SetConcrete(col.next(), v.Interface())
+	case ft == col.field.Type().NullableType():
+		col.nullable()
+		col.field.Set(col.next(), v.Interface())
+	default:
+		return fmt.Errorf("mismatched types in %s: %s and %s", col.field.Name, col.field.Type().ItemTypeString(), ft.ItemTypeString())
+	}
+	return nil
+}
+
+// next makes room for another value and returns its index
+func (col *column) next() int {
+	if col.len == col.field.Len() {
+		col.field.Extend(1)
+	}
+	col.len++
+	return col.len - 1
+}
+
+// pad back-fills the column with nulls until it has n values
+func (col *column) pad(n int) {
+	col.nullable()
+	if col.field.Len() < n {
+		col.field.Extend(n - col.field.Len())
+	}
+	col.len = n
+}
+
+// nullable switches the column to the nullable version of its type
+func (col *column) nullable() {
+	f := col.field
+	if f.Nullable() {
+		return
+	}
+
+	n := data.NewFieldFromFieldType(f.Type().NullableType(), f.Len())
+	n.Name = f.Name
+	n.Labels = f.Labels
+	n.Config = f.Config
+	for i := 0; i < col.len; i++ {
+		n.Set(i, f.PointerAt(i))
+	}
+	col.field = n
+}
+
+// finish drops the values that were preallocated but never written
+func (col *column) finish() *data.Field {
+	col.truncate(col.len)
+	return col.field
+}
+
+// truncate drops every value after the first n
+func (col *column) truncate(n int) {
+	for col.field.Len() > n {
+		col.field.Delete(col.field.Len() - 1)
+	}
+	col.len = n
+}
@@ -0,0 +1,142 @@
+package framestruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ToDataFrameColumns converts values that are already in columns into a
+// *data.Frame. columns must be a map of slices, like map[string][]float64,
+// or a struct whose fields are slices, like
+//
+//	struct {
+//		Time  []time.Time
+//		Value []float64
+//	}
+//
+// Every slice becomes a field and must have the same length. Fields are named
+// and ordered like ToDataFrame names and orders the keys of maps and the
+// fields of structs.
+func ToDataFrameColumns(name string, columns interface{}, opts ...Option) (*data.Frame, error) {
+	c := newConverter(opts...)
+	v := c.ensureValue(reflect.ValueOf(columns))
+
+	var err error
+	switch {
+	case !v.IsValid():
+		return nil, errors.New("unsupported type: columns must be a map or a struct of slices")
+	case v.Kind() == reflect.Map:
+		err = c.convertColumnMap(v)
+	case isNestedStruct(v.Type()):
+		err = c.convertColumnStruct(v)
+	default:
+		return nil, errors.New("unsupported type: columns must be a map or a struct of slices")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.setMetaProvider(columns)
+	return c.createFrame(name)
+}
+
+func (c *converter) convertColumnMap(m reflect.Value) error {
+	c.anyMap = true
+	if !supportedKeyType(m.Type().Key()) {
+		return fmt.Errorf("unsupported type %s: map keys must be strings, integers, or implement encoding.TextMarshaler or fmt.Stringer", m.Type())
+	}
+
+	keys, err := c.mapKeys(m)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		name := c.cellName(c.mapKeyPath(key, fieldTags{}, nil))
+		if err := c.addColumn(m.MapIndex(key.value), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *converter) convertColumnStruct(v reflect.Value) error {
+	p, err := c.plan(v.Type(), nil)
+	if err != nil {
+		return err
+	}
+
+	for _, fp := range p.fields {
+		if fp.tags.col0 {
+			c.col0 = fp.name
+		}
+		if err := c.addColumn(v.Field(fp.index), fp.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn adds the slice v to the frame as the field called name
+func (c *converter) addColumn(v reflect.Value, name string) error {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return c.unsupported(fmt.Errorf("column %s is nil", name))
+	}
+	if v.Kind() != reflect.Slice || !isScalar(v.Type().Elem()) {
+		return c.unsupported(fmt.Errorf("unsupported type %s: columns must be slices of values", v.Type()))
+	}
+
+	if _, exists := c.fields[name]; exists {
+		return fmt.Errorf("more than one column is called %s", name)
+	}
+
+	n := v.Len()
+	if len(c.fieldNames) == 0 {
+		c.rows = n
+	} else if n != c.rows {
+		return fmt.Errorf("columns must have the same length: %s has %d values and %s has %d", c.fieldNames[0], c.rows, name, n)
+	}
+
+	f, err := c.columnField(name, v)
+	if err != nil {
+		return err
+	}
+	if tags, ok := c.configs[name]; ok {
+		// tags are validated when the plan is compiled
+		f.Config, _ = tags.fieldConfig()
+	}
+
+	c.fieldNames = append(c.fieldNames, name)
+	c.fields[name] = &column{field: f, len: n}
+	return nil
+}
+
+// columnField returns a field with the values of the slice v. Slices of the
+// types fields store are copied as a whole. Other slices, like []int or
+// []Celsius, are converted a value at a time.
+func (c *converter) columnField(name string, v reflect.Value) (*data.Field, error) {
+	et := v.Type().Elem()
+	ft := fieldType(derefType(et))
+	if derefType(et) == ft && (ft != timeType || c.location == nil) {
+		values := reflect.MakeSlice(reflect.SliceOf(et), v.Len(), v.Len())
+		reflect.Copy(values, v)
+		return data.NewField(name, nil, values.Interface()), nil
+	}
+
+	col, err := newColumn(name, fieldValue(reflect.Zero(et)), v.Len())
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := col.append(c.inLocation(fieldValue(v.Index(i)))); err != nil {
+			return nil, err
+		}
+	}
+	return col.finish(), nil
+}
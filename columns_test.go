@@ -0,0 +1,91 @@
+package framestruct_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToDataFrameColumns(t *testing.T) {
+	tme := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+
+	t.Run("it converts a struct of slices into a field for each slice", func(t *testing.T) {
+		cols := columnStruct{
+			Time:  []time.Time{tme, tme.Add(time.Minute)},
+			Value: []float64{1.5, 2.5},
+			Count: []int{1, 2},
+			Temp:  []*celsius{nil, new(celsius)},
+		}
+
+		frame, err := framestruct.ToDataFrameColumns("results", cols)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"value", "Time", "Count", "Temp"}, fieldNames(frame))
+		require.Equal(t, 2.5, frame.Fields[0].At(1))
+		require.Equal(t, "ms", frame.Fields[0].Config.Unit)
+		require.Equal(t, tme, frame.Fields[1].At(0))
+		require.Equal(t, int64(2), frame.Fields[2].At(1))
+		require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[3].Type())
+		require.Nil(t, frame.Fields[3].At(0))
+	})
+
+	t.Run("it copies the slices", func(t *testing.T) {
+		values := []float64{1.5, 2.5}
+
+		frame, err := framestruct.ToDataFrameColumns("results", map[string][]float64{"a": values})
+		require.Nil(t, err)
+
+		values[0] = 3
+		require.Equal(t, 1.5, frame.Fields[0].At(0))
+	})
+
+	t.Run("it converts a map of slices into a field for each key", func(t *testing.T) {
+		cols := map[string]interface{}{
+			"value": []float64{1.5, 2.5},
+			"host":  []string{"a", "b"},
+			"time":  []time.Time{tme, tme.Add(time.Minute)},
+		}
+
+		frame, err := framestruct.ToDataFrameColumns("results", cols, framestruct.WithTimeSort(framestruct.AllowDuplicateTimes))
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"time", "host", "value"}, fieldNames(frame))
+		require.Equal(t, "b", frame.Fields[1].At(1))
+	})
+
+	t.Run("it returns an error when columns have different lengths", func(t *testing.T) {
+		cols := map[string][]float64{
+			"a": {1, 2},
+			"b": {1},
+		}
+
+		_, err := framestruct.ToDataFrameColumns("results", cols, framestruct.WithMapKeyOrder(framestruct.SortMapKeys))
+		require.Error(t, err)
+		require.Equal(t, "columns must have the same length: a has 2 values and b has 1", err.Error())
+	})
+
+	t.Run("it returns an error for values that aren't columns", func(t *testing.T) {
+		_, err := framestruct.ToDataFrameColumns("results", []float64{1})
+		require.Error(t, err)
+
+		_, err = framestruct.ToDataFrameColumns("results", map[string]interface{}{"a": 1.5})
+		require.Error(t, err)
+
+		_, err = framestruct.ToDataFrameColumns("results", simpleStruct{"foo", 36, "baz"})
+		require.Error(t, err)
+
+		_, err = framestruct.ToDataFrameColumns("results", nil)
+		require.Error(t, err)
+	})
+}
+
+type columnStruct struct {
+	Time  []time.Time
+	Value []float64 `frame:"value,col0,unit=ms"`
+	Count []int
+	Temp  []*celsius
+	Skip  []string `frame:"-"`
+}
@@ -0,0 +1,50 @@
+package framestruct
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func (t fieldTags) hasConfig() bool {
+	return t.unit != "" || t.displayName != "" || t.decimals != "" || t.min != "" || t.max != ""
+}
+
+// fieldConfig returns the data.FieldConfig described by the unit,
+// displayName, decimals, min, and max options, or nil if there are none
+func (t fieldTags) fieldConfig() (*data.FieldConfig, error) {
+	if !t.hasConfig() {
+		return nil, nil
+	}
+
+	config := &data.FieldConfig{
+		Unit:        t.unit,
+		DisplayName: t.displayName,
+	}
+
+	if t.decimals != "" {
+		decimals, err := strconv.ParseUint(t.decimals, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("decimals must be a whole number: %q", t.decimals)
+		}
+		config.SetDecimals(uint16(decimals))
+	}
+
+	if t.min != "" {
+		min, err := strconv.ParseFloat(t.min, 64)
+		if err != nil {
+			return nil, fmt.Errorf("min must be a number: %q", t.min)
+		}
+		config.SetMin(min)
+	}
+
+	if t.max != "" {
+		max, err := strconv.ParseFloat(t.max, 64)
+		if err != nil {
+			return nil, fmt.Errorf("max must be a number: %q", t.max)
+		}
+		config.SetMax(max)
+	}
+	return config, nil
+}
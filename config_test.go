@@ -0,0 +1,71 @@
+package framestruct_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldConfig(t *testing.T) {
+	t.Run("it sets the field config from tags", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", configuredStruct{"foo", 1.5, 0.25})
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Name", "latency", "ratio"}, fieldNames(frame))
+		require.Nil(t, frame.Fields[0].Config)
+
+		expected := (&data.FieldConfig{Unit: "ms", DisplayName: "Latency"}).SetDecimals(2)
+		require.Equal(t, expected, frame.Fields[1].Config)
+
+		expected = (&data.FieldConfig{Unit: "percentunit"}).SetMin(0).SetMax(1)
+		require.Equal(t, expected, frame.Fields[2].Config)
+	})
+
+	t.Run("it gives every frame its own field config", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", configuredStruct{"foo", 1.5, 0.25})
+		require.Nil(t, err)
+		frame.Fields[1].Config.Unit = "s"
+
+		frame, err = framestruct.ToDataFrame("results", configuredStruct{"foo", 1.5, 0.25})
+		require.Nil(t, err)
+		require.Equal(t, "ms", frame.Fields[1].Config.Unit)
+	})
+
+	t.Run("it keeps the field config when a field becomes nullable", func(t *testing.T) {
+		latency := 2.5
+		rows := []map[string]interface{}{
+			{"a": configuredStruct{"foo", 1.5, 0.25}},
+			{"a": configuredPointer{&latency}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows)
+		require.Nil(t, err)
+
+		f := frame.Fields[1]
+		require.Equal(t, "a.latency", f.Name)
+		require.True(t, f.Nullable())
+		require.Equal(t, "ms", f.Config.Unit)
+	})
+
+	t.Run("it returns an error when the field config is invalid", func(t *testing.T) {
+		_, err := framestruct.ToDataFrame("results", invalidConfig{1})
+		require.Error(t, err)
+		require.Equal(t, `invalid frame tag on Latency: decimals must be a whole number: "two"`, err.Error())
+	})
+}
+
+type configuredStruct struct {
+	Name    string
+	Latency float64 `frame:"latency,unit=ms,decimals=2,displayName=Latency"`
+	Ratio   float64 `frame:"ratio,unit=percentunit,min=0,max=1"`
+}
+
+type configuredPointer struct {
+	Latency *float64 `frame:"latency,unit=ms"`
+}
+
+type invalidConfig struct {
+	Latency float64 `frame:"latency,decimals=two"`
+}
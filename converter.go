@@ -2,32 +2,95 @@ package framestruct
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
-const frameTag = "frame"
+const (
+	frameTag         = "frame"
+	defaultSeparator = "."
+)
 
 type converter struct {
 	fieldNames []string
-	fields     map[string]*data.Field
-	tags       []string
+	fields     map[string]*column
+	row        *row
 	anyMap     bool
 	col0       string
+	rows       int
+	size       int
+	plans      map[planKey]*structPlan
+
+	// the columns of fields tagged label or value
+	labelFields map[string]bool
+	valueFields map[string]bool
+	// the tags of columns that have a field config
+	configs map[string]fieldTags
+	// the order tags of columns
+	orders map[string]int
+	// the paths values under integer map keys are sorted by, and the names
+	// of their columns are sorted by
+	sortPaths map[string][]string
+	sortKeys  map[string]string
+
+	// labels are the values of fields tagged label, unless they're kept
+	// as columns
+	labels     data.Labels
+	labelNames []string
+	keepLabels bool
+
+	// meta is the metadata of a FrameMetaProvider. metaOpts are applied on
+	// top of it.
+	meta     *data.FrameMeta
+	metaOpts []func(*data.FrameMeta)
+
+	tagKey          string
+	separator       string
+	namer           FieldNamer
+	nameTransform   func(string) string
+	mapKeyOrder     MapKeyOrder
+	columnOrder     []string
+	scalarName      string
+	nullRows        bool
+	skipUnsupported bool
+	stringFallback  bool
+	location        *time.Location
+	maxRows         int
+	timeSort        bool
+	duplicateTimes  DuplicateTimes
+	fillMissing     *data.FillMissing
 }
 
-// ToDataFrame flattens an arbitrary struct or slice of structs into a *data.Frame
-func ToDataFrame(name string, toConvert interface{}) (*data.Frame, error) {
-	cr := &converter{
-		fields: make(map[string]*data.Field),
-		tags:   make([]string, 3),
+func newConverter(opts ...Option) *converter {
+	c := &converter{
+		fields:      make(map[string]*column),
+		row:         &row{},
+		plans:       make(map[planKey]*structPlan),
+		labelFields: make(map[string]bool),
+		valueFields: make(map[string]bool),
+		configs:     make(map[string]fieldTags),
+		orders:      make(map[string]int),
+		sortPaths:   make(map[string][]string),
+		sortKeys:    make(map[string]string),
+		labels:      make(data.Labels),
+		tagKey:      frameTag,
+		separator:   defaultSeparator,
 	}
 
-	return cr.toDataframe(name, toConvert)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ToDataFrame flattens an arbitrary struct or slice of structs into a *data.Frame.
+// Conversion can be customized with Options.
+func ToDataFrame(name string, toConvert interface{}, opts ...Option) (*data.Frame, error) {
+	return newConverter(opts...).toDataframe(name, toConvert)
 }
 
 // ToDataFrames is a convenience wrapper around ToDataFrame. It will wrap the
@@ -36,13 +99,13 @@ func ToDataFrame(name string, toConvert interface{}) (*data.Frame, error) {
 // for the type conversion. If this function delegates to a data.Framer, it
 // will use the data.Frame name defined by the type rather than passed to this
 // function
-func ToDataFrames(name string, toConvert interface{}) (data.Frames, error) {
+func ToDataFrames(name string, toConvert interface{}, opts ...Option) (data.Frames, error) {
 	framer, ok := toConvert.(data.Framer)
 	if ok {
 		return framer.Frames()
 	}
 
-	frame, err := ToDataFrame(name, toConvert)
+	frame, err := ToDataFrame(name, toConvert, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -56,11 +119,89 @@ func (c *converter) toDataframe(name string, toConvert interface{}) (*data.Frame
 		return nil, errors.New("unsupported type: can only convert structs, slices, and maps")
 	}
 
-	if err := c.handleValue(v, "", ""); err != nil {
+	c.setMetaProvider(toConvert)
+	c.nameScalars(name)
+	if err := c.convertRows(v); err != nil {
 		return nil, err
 	}
 
-	return c.createFrame(name), nil
+	return c.createFrame(name)
+}
+
+// convertRows converts each element of a top level slice into its own row.
+// Anything else is converted into a single row.
+func (c *converter) convertRows(v reflect.Value) error {
+	if v.Kind() != reflect.Slice {
+		if err := c.handleValue(v, fieldTags{}, nil); err != nil {
+			return err
+		}
+		if err := c.writeRow(); err != nil {
+			return err
+		}
+
+		if c.maxRows > 0 && c.rows > c.maxRows {
+			return ErrMaxRows
+		}
+		return nil
+	}
+
+	// every element is at least one row
+	c.size = v.Len()
+	scalars := isScalar(v.Type().Elem())
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if !scalars {
+			// the elements of slices like []*T are the structs they point to
+			elem = indirect(elem)
+		}
+
+		var err error
+		switch {
+		case elem.IsValid():
+			err = c.appendRow(elem)
+		case c.nullRows:
+			err = c.appendNullRow()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendRow converts an element of a top level slice. When it can't be
+// converted, none of its values are kept.
+func (c *converter) appendRow(v reflect.Value) error {
+	if c.maxRows > 0 && c.rows >= c.maxRows {
+		return ErrMaxRows
+	}
+
+	rows, fields, labels := c.rows, len(c.fieldNames), len(c.labelNames)
+	if err := c.convertElem(v, nil); err != nil {
+		c.row.reset()
+		c.truncate(rows, fields, labels)
+		return err
+	}
+
+	if err := c.writeRow(); err != nil {
+		c.truncate(rows, fields, labels)
+		return err
+	}
+
+	if c.maxRows > 0 && c.rows > c.maxRows {
+		// the element exploded into more rows than are left
+		c.truncate(rows, fields, labels)
+		return ErrMaxRows
+	}
+	return nil
+}
+
+// appendNullRow adds a row where every value is null
+func (c *converter) appendNullRow() error {
+	if c.maxRows > 0 && c.rows >= c.maxRows {
+		return ErrMaxRows
+	}
+	return c.writeRow()
 }
 
 func (c *converter) ensureValue(v reflect.Value) reflect.Value {
@@ -70,179 +211,362 @@ func (c *converter) ensureValue(v reflect.Value) reflect.Value {
 	return v
 }
 
-func (c *converter) handleValue(field reflect.Value, tags, fieldName string) error {
+func (c *converter) handleValue(field reflect.Value, tags fieldTags, path []string) error {
+	if field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			// nil values are back-filled with nulls
+			return nil
+		}
+		field = field.Elem()
+	}
+
+	value, ok, err := frameValue(field)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if value == nil {
+			// nil values are back-filled with nulls
+			return nil
+		}
+
+		v := c.ensureValue(reflect.ValueOf(value))
+		if indirectType(v.Type()) == indirectType(field.Type()) {
+			// a type that returns itself is converted like any other value
+			return c.convertValue(v, tags, path)
+		}
+		return c.handleValue(v, tags, path)
+	}
+	return c.convertValue(field, tags, path)
+}
+
+// convertValue converts field by its kind, without calling FrameValue
+func (c *converter) convertValue(field reflect.Value, tags fieldTags, path []string) error {
+	text, ok, err := c.textValue(field, tags.asString)
+	if err != nil {
+		return err
+	}
+	if ok {
+		c.addCell(text, path)
+		return nil
+	}
+
 	switch field.Kind() {
 	case reflect.Slice:
-		return c.convertSlice(field, fieldName)
+		if isScalarSlice(field.Type()) {
+			return c.convertScalarSlice(field, tags, path)
+		}
+		return c.convertSlice(field, path)
 	case reflect.Struct:
-		return c.convertStruct(field, fieldName)
+		return c.convertStruct(field, path)
 	case reflect.Map:
-		return c.convertMap(field.Interface(), tags, fieldName)
+		return c.convertMap(field, tags, path)
 	default:
-		return c.upsertField(field, fieldName)
+		c.addCell(field, path)
+		return nil
 	}
 }
 
-func (c *converter) convertStruct(field reflect.Value, fieldName string) error {
-	_, ok := field.Interface().(time.Time)
-	if ok {
-		return c.upsertField(field, fieldName)
+func (c *converter) convertStruct(field reflect.Value, path []string) error {
+	if fieldType(field.Type()) != nil {
+		// times are structs, but they're stored as values
+		c.addCell(field, path)
+		return nil
 	}
 
-	return c.convertStructFields(field, fieldName)
+	return c.convertStructFields(field, path)
 }
 
-func (c *converter) convertSlice(s reflect.Value, prefix string) error {
+// convertSlice converts a slice nested in a row. Each element becomes its own
+// row alongside the rest of the values in the row.
+func (c *converter) convertSlice(s reflect.Value, path []string) error {
+	parent := c.row
+	defer func() { c.row = parent }()
+
+	children := make([]*row, 0, s.Len())
 	for i := 0; i < s.Len(); i++ {
-		v := s.Index(i)
-		switch v.Kind() {
-		case reflect.Map:
-			if err := c.convertMap(v.Interface(), "", prefix); err != nil {
-				return err
-			}
-		default:
-			if err := c.convertStruct(v, prefix); err != nil {
+		c.row = &row{}
+		elem := indirect(s.Index(i))
+		switch {
+		case elem.IsValid():
+			if err := c.convertElem(elem, path); err != nil {
 				return err
 			}
+		case !c.nullRows:
+			continue
 		}
+		children = append(children, c.row)
 	}
+
+	parent.addGroup(children)
 	return nil
 }
 
-func (c *converter) convertStructFields(v reflect.Value, prefix string) error {
+func (c *converter) convertElem(v reflect.Value, path []string) error {
+	if implements(v.Type(), valuerType) {
+		return c.handleValue(v, fieldTags{}, path)
+	}
+	if v.Kind() == reflect.Map {
+		return c.convertMap(v, fieldTags{}, path)
+	}
+	if path == nil && isScalar(v.Type()) {
+		// the elements of a top level slice of values
+		return c.handleValue(v, fieldTags{}, []string{c.scalarName})
+	}
+	return c.convertStruct(v, path)
+}
+
+func (c *converter) convertStructFields(v reflect.Value, path []string) error {
 	if v.Kind() != reflect.Struct {
-		return errors.New("unsupported type: converted types may not contain slices")
+		return c.unsupported(errors.New("unsupported type: converted types may not contain slices"))
 	}
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		if !exported(field) {
-			continue
-		}
+	p, err := c.plan(v.Type(), path)
+	if err != nil {
+		return err
+	}
+	return c.convertPlan(v, p)
+}
 
-		structField := v.Type().Field(i)
-		tags := structField.Tag.Get(frameTag)
+// convertMap flattens a map into a column for each key
+func (c *converter) convertMap(m reflect.Value, tags fieldTags, path []string) error {
+	c.anyMap = true
+	if !supportedKeyType(m.Type().Key()) {
+		return c.unsupported(fmt.Errorf("unsupported type %s: map keys must be strings, integers, or implement encoding.TextMarshaler or fmt.Stringer", m.Type()))
+	}
+
+	keys, err := c.mapKeys(m)
+	if err != nil {
+		return err
+	}
 
-		if tags == "-" {
+	for _, key := range keys {
+		value := m.MapIndex(key.value)
+		if value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		if !value.IsValid() || value.Kind() == reflect.Ptr && value.IsNil() {
+			// missing values are back-filled with nulls
 			continue
 		}
 
-		fieldName := c.fieldName(structField.Name, tags, prefix)
-		if err := c.handleValue(field, tags, fieldName); err != nil {
+		fieldPath := c.mapKeyPath(key, tags, path)
+		if err := c.handleValue(c.ensureValue(value), fieldTags{}, fieldPath); err != nil {
 			return err
 		}
-
-		c.parseTags(tags)
-		if c.tags[2] != "" {
-			c.col0 = fieldName
-		}
 	}
+
 	return nil
 }
 
-func exported(v reflect.Value) bool {
-	return v.CanInterface()
+func (c *converter) addCell(v reflect.Value, path []string) {
+	c.row.cells = append(c.row.cells, cell{c.cellName(path), v})
 }
 
-func (c *converter) convertMap(toConvert interface{}, tags, prefix string) error {
-	c.anyMap = true
-	m, ok := toConvert.(map[string]interface{})
-	if !ok {
-		return errors.New("map must be map[string]interface{}")
+// writeRow writes the current row to the fields. A row that contains nested
+// slices is written once for every combination of their elements.
+func (c *converter) writeRow() error {
+	defer c.row.reset()
+
+	if len(c.row.groups) == 0 {
+		return c.writeCells(c.row.cells)
 	}
 
-	for name, value := range m {
-		fieldName := c.fieldName(name, tags, prefix)
-		v := c.ensureValue(reflect.ValueOf(value))
-		if err := c.handleValue(v, "", fieldName); err != nil {
+	for _, cells := range c.row.expand() {
+		if err := c.writeCells(cells); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
+func (c *converter) writeCells(cells []cell) error {
+	for _, cell := range cells {
+		if err := c.upsertField(cell.value, cell.name); err != nil {
+			return err
+		}
+	}
+	c.endRow()
 	return nil
 }
 
+// unsupported returns err unless the converter skips unsupported values
+func (c *converter) unsupported(err error) error {
+	if c.skipUnsupported {
+		return nil
+	}
+	return err
+}
+
 func (c *converter) upsertField(v reflect.Value, fieldName string) error {
-	if _, exists := c.fields[fieldName]; !exists {
+	v = c.inLocation(fieldValue(v))
+	col, exists := c.fields[fieldName]
+	if !exists {
+		var err error
+		col, err = newColumn(fieldName, v, c.size)
+		if err != nil {
+			return c.unsupported(err)
+		}
+
+		if tags, ok := c.configs[fieldName]; ok {
+			// tags are validated when the plan is compiled
+			col.field.Config, _ = tags.fieldConfig()
+		}
+
 		// keep track of unique fields in the order they appear
 		c.fieldNames = append(c.fieldNames, fieldName)
-		v, err := sliceFor(v.Interface())
-		if err != nil {
-			return err
+		c.fields[fieldName] = col
+
+		if c.rows > 0 {
+			// the field is new to this row, so every row before it is null
+			col.pad(c.rows)
 		}
+	}
+	return col.append(v)
+}
 
-		c.fields[fieldName] = data.NewField(fieldName, nil, v)
+// endRow finishes the current row by back-filling every field that didn't
+// receive a value with a null, so that all fields stay the same length
+func (c *converter) endRow() {
+	rows := c.rows + 1
+	for _, col := range c.fields {
+		if col.len > rows {
+			rows = col.len
+		}
 	}
-	c.fields[fieldName].Append(v.Interface())
-	return nil
+
+	for _, col := range c.fields {
+		if col.len < rows {
+			col.pad(rows)
+		}
+	}
+
+	c.rows = rows
+}
+
+// truncate drops the values written after the first rows rows, and the
+// fields and labels added after the first fields fields and labels labels
+func (c *converter) truncate(rows, fields, labels int) {
+	for _, name := range c.fieldNames[fields:] {
+		delete(c.fields, name)
+	}
+	c.fieldNames = c.fieldNames[:fields]
+
+	for _, name := range c.labelNames[labels:] {
+		delete(c.labels, name)
+	}
+	c.labelNames = c.labelNames[:labels]
+
+	for _, col := range c.fields {
+		col.truncate(rows)
+	}
+	c.rows = rows
 }
 
-func (c *converter) createFrame(name string) *data.Frame {
+func (c *converter) createFrame(name string) (*data.Frame, error) {
 	frame := data.NewFrame(name)
-	for _, f := range c.getFieldnames() {
-		frame.Fields = append(frame.Fields, c.fields[f])
+	fieldnames, sortByTime := c.getFieldnames()
+	for _, f := range fieldnames {
+		frame.Fields = append(frame.Fields, c.fields[f].finish())
+	}
+	c.applyLabels(frame)
+	frame.Meta = c.frameMeta()
+
+	if sortByTime {
+		if err := c.sortByTime(frame); err != nil {
+			return nil, err
+		}
+	}
+	return frame, nil
+}
+
+// nameScalars names the column of a frame converted from a slice of values
+// after the frame, unless WithColumnName set its name
+func (c *converter) nameScalars(frameName string) {
+	if c.scalarName == "" {
+		c.scalarName = frameName
 	}
-	return frame
 }
 
-func (c *converter) getFieldnames() []string {
-	if c.anyMap {
+// getFieldnames returns the names of the fields in the order they go in the
+// frame, and whether the rows should be sorted by the 0th field. The time
+// field of WithTimeSort goes first, followed by the columns of
+// WithColumnOrder, the col0 field, and fields tagged with an order. The rest
+// keep their order.
+func (c *converter) getFieldnames() ([]string, bool) {
+	if c.anyMap && c.mapKeyOrder == SortAllFields {
 		// Ensure stable order of fields across
 		// runs, because maps
-		sort.Strings(c.fieldNames)
+		if len(c.sortPaths) == 0 {
+			sort.Strings(c.fieldNames)
+		} else {
+			sort.Slice(c.fieldNames, func(i, j int) bool {
+				return c.sortKey(c.fieldNames[i]) < c.sortKey(c.fieldNames[j])
+			})
+		}
 	}
 
-	fieldnames := []string{}
-	if c.col0 != "" {
-		fieldnames = append(fieldnames, c.col0)
-	}
-	for _, f := range c.fieldNames {
-		if f != c.col0 {
-			fieldnames = append(fieldnames, f)
+	fieldnames := make([]string, 0, len(c.fieldNames))
+	placed := make(map[string]bool, len(c.fieldNames))
+	place := func(name string) {
+		if _, ok := c.fields[name]; ok && !placed[name] {
+			placed[name] = true
+			fieldnames = append(fieldnames, name)
 		}
 	}
 
-	return fieldnames
-}
-
-func (c *converter) fieldName(fieldName, tags, prefix string) string {
-	c.parseTags(tags)
-	if c.tags[1] == "omitparent" {
-		prefix = ""
+	sortByTime := false
+	if c.timeSort {
+		if name := c.timeField(); name != "" {
+			place(name)
+			sortByTime = true
+		}
 	}
 
-	if c.tags[0] != "" {
-		fieldName = c.tags[0]
+	for _, name := range c.columnOrder {
+		place(name)
 	}
+	place(c.col0)
 
-	if prefix == "" {
-		return fieldName
+	for _, name := range c.orderedFieldnames() {
+		place(name)
+	}
+	for _, name := range c.fieldNames {
+		place(name)
 	}
 
-	return prefix + "." + fieldName
+	return fieldnames, sortByTime
 }
 
-func (c *converter) parseTags(s string) {
-	// if we do it this way, we avoid all the allocs
-	// of strings.Split
-	c.tags[0] = ""
-	c.tags[1] = ""
-	c.tags[2] = ""
-
-	sep := ","
+// orderedFieldnames returns the names of the fields tagged with an order,
+// sorted by it
+func (c *converter) orderedFieldnames() []string {
+	if len(c.orders) == 0 {
+		return nil
+	}
 
-	i := 0
-	for i < 2 {
-		m := strings.Index(s, sep)
-		if m < 0 {
-			break
+	var names []string
+	for _, name := range c.fieldNames {
+		if _, ok := c.orders[name]; ok {
+			names = append(names, name)
 		}
-		c.tags[i] = strings.TrimSpace(s[:m])
-		s = s[m+len(sep):]
-		i++
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return c.orders[names[i]] < c.orders[names[j]]
+	})
+	return names
+}
+
+// fieldPath returns the path to the child called name of the value at path
+func (c *converter) fieldPath(name string, tags fieldTags, path []string) []string {
+	if tags.omitParent {
+		path = nil
 	}
 
-	if i < len(c.tags) {
-		c.tags[i] = s
+	if tags.name != "" {
+		name = tags.name
 	}
+
+	// limit the capacity so siblings never share a backing array
+	return append(path[:len(path):len(path)], name)
 }
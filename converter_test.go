@@ -1,6 +1,8 @@
 package framestruct_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -145,23 +147,40 @@ func TestStructs(t *testing.T) {
 
 		_, err := framestruct.ToDataFrame("results", strct)
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
+	})
+
+	t.Run("it names every occurrence of a nested type after its own parent", func(t *testing.T) {
+		strcts := []repeatedNested{
+			{nested3{true, 100}, nested3{false, 200}},
+			{nested3{false, 101}, nested3{true, 201}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"First.Thing7", "First.Thing8", "Second.Thing7", "Second.Thing8"}, fieldNames(frame))
+		require.Equal(t, int64(201), frame.Fields[3].At(1))
+
+		frame, err = framestruct.ToDataFrame("results", strcts, framestruct.WithSeparator("_"))
+		require.Nil(t, err)
+		require.Equal(t, []string{"First_Thing7", "First_Thing8", "Second_Thing7", "Second_Thing8"}, fieldNames(frame))
 	})
 
 	t.Run("it returns an error when any struct contains a map with an unsupported type", func(t *testing.T) {
 		m := structWithMap{
 			map[string]interface{}{
-				"Thing2": 36,
+				"Thing2": complex64(36),
 			},
 		}
 
 		_, err := framestruct.ToDataFrame("results", m)
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
 
 		_, err = framestruct.ToDataFrame("results", []structWithMap{m})
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
 	})
 
 	t.Run("it returns an error when a nested struct contains an unsupported type", func(t *testing.T) {
@@ -174,7 +193,7 @@ func TestStructs(t *testing.T) {
 
 		_, err := framestruct.ToDataFrame("results", strct)
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
 	})
 
 	t.Run("it returns an error when any struct contains an unsupported type", func(t *testing.T) {
@@ -182,7 +201,7 @@ func TestStructs(t *testing.T) {
 
 		_, err := framestruct.ToDataFrame("results", []unsupportedType{strct})
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
 	})
 
 	t.Run("it can't convert a struct that contains a slice", func(t *testing.T) {
@@ -194,6 +213,101 @@ func TestStructs(t *testing.T) {
 	})
 }
 
+func TestKinds(t *testing.T) {
+	t.Run("it widens ints and uints to 64 bits", func(t *testing.T) {
+		i, u := 3, uint(4)
+		strct := intStruct{1, 2, uintptr(5), &i, &u}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 5)
+		require.Equal(t, int64(1), frame.Fields[0].At(0))
+		require.Equal(t, uint64(2), frame.Fields[1].At(0))
+		require.Equal(t, uint64(5), frame.Fields[2].At(0))
+		require.Equal(t, int64(3), *frame.Fields[3].At(0).(*int64))
+		require.Equal(t, uint64(4), *frame.Fields[4].At(0).(*uint64))
+	})
+
+	t.Run("it stores named types as their underlying type", func(t *testing.T) {
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+		strct := namedTypes{21.5, "ok", 7, namedTime(tme)}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 4)
+		require.Equal(t, 21.5, frame.Fields[0].At(0))
+		require.Equal(t, "ok", frame.Fields[1].At(0))
+		require.Equal(t, int32(7), frame.Fields[2].At(0))
+		require.Equal(t, tme, frame.Fields[3].At(0))
+	})
+
+	t.Run("it converts ints in maps", func(t *testing.T) {
+		m := map[string]interface{}{
+			"Thing1": 36,
+			"Thing2": celsius(21.5),
+		}
+
+		frame, err := framestruct.ToDataFrame("results", m)
+		require.Nil(t, err)
+
+		require.Equal(t, int64(36), frame.Fields[0].At(0))
+		require.Equal(t, 21.5, frame.Fields[1].At(0))
+	})
+
+	t.Run("it converts the values held by interface fields", func(t *testing.T) {
+		strcts := []interfaceStruct{{"foo"}, {"bar"}}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, "foo", frame.Fields[0].At(0))
+		require.Equal(t, "bar", frame.Fields[0].At(1))
+	})
+
+	t.Run("it converts nil interface fields into nulls", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", []interfaceStruct{{1.5}, {nil}})
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, 1.5, *frame.Fields[0].At(0).(*float64))
+		require.Nil(t, frame.Fields[0].At(1))
+
+		frame, err = framestruct.ToDataFrame("results", []interfaceStruct{{nil}, {1.5}})
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 1)
+		require.Nil(t, frame.Fields[0].At(0))
+		require.Equal(t, 1.5, *frame.Fields[0].At(1).(*float64))
+	})
+
+	t.Run("it decodes into ints and named types", func(t *testing.T) {
+		i, u := 3, uint(4)
+		ints := intStruct{1, 2, uintptr(5), &i, &u}
+
+		frame, err := framestruct.ToDataFrame("results", ints)
+		require.Nil(t, err)
+
+		var outInts intStruct
+		err = framestruct.FromDataFrame(frame, &outInts)
+		require.Nil(t, err)
+		require.Equal(t, ints, outInts)
+
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+		named := namedTypes{21.5, "ok", 7, namedTime(tme)}
+
+		frame, err = framestruct.ToDataFrame("results", named)
+		require.Nil(t, err)
+
+		var outNamed namedTypes
+		err = framestruct.FromDataFrame(frame, &outNamed)
+		require.Nil(t, err)
+		require.Equal(t, named, outNamed)
+	})
+}
+
 func TestSlices(t *testing.T) {
 	t.Run("it flattens a slice of structs", func(t *testing.T) {
 		strct := []simpleStruct{
@@ -243,6 +357,58 @@ func TestSlices(t *testing.T) {
 		require.Equal(t, "baz1", frame.Fields[2].At(1))
 	})
 
+	t.Run("it flattens slices of pointers to structs and skips nil elements", func(t *testing.T) {
+		first := &simpleStruct{"foo", 36, "baz"}
+		strcts := []*simpleStruct{first, nil, {"foo1", 37, "baz1"}}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Equal(t, "foo1", frame.Fields[0].At(1))
+		require.Equal(t, int32(37), frame.Fields[1].At(1))
+
+		frame, err = framestruct.ToDataFrame("results", []**simpleStruct{&first, nil})
+		require.Nil(t, err)
+		require.Equal(t, 1, frame.Fields[0].Len())
+
+		frame, err = framestruct.Convert("results", strcts)
+		require.Nil(t, err)
+		require.Equal(t, 2, frame.Fields[0].Len())
+	})
+
+	t.Run("it converts nil elements into null rows with WithNullRows", func(t *testing.T) {
+		strcts := []*simpleStruct{nil, {"foo", 36, "baz"}}
+
+		frame, err := framestruct.ToDataFrame("results", strcts, framestruct.WithNullRows())
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Thing1", "Thing2", "Thing3"}, fieldNames(frame))
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Nil(t, frame.Fields[0].At(0))
+		require.Nil(t, frame.Fields[1].At(0))
+		require.Equal(t, "foo", *frame.Fields[0].At(1).(*string))
+	})
+
+	t.Run("it flattens nested slices of pointers to structs", func(t *testing.T) {
+		strct := pointerSliceStruct{
+			Name:   "foo",
+			Things: []*nested3{{true, 100}, nil, {false, 101}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+		require.Equal(t, []string{"Name", "Things.Thing7", "Things.Thing8"}, fieldNames(frame))
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Equal(t, int64(101), frame.Fields[2].At(1))
+
+		frame, err = framestruct.ToDataFrame("results", strct, framestruct.WithNullRows())
+		require.Nil(t, err)
+		require.Equal(t, 3, frame.Fields[0].Len())
+		require.Nil(t, frame.Fields[2].At(1))
+	})
+
 	t.Run("it flattens a slice of maps", func(t *testing.T) {
 		maps := []map[string]interface{}{
 			{
@@ -274,6 +440,78 @@ func TestSlices(t *testing.T) {
 		require.Equal(t, "baz", frame.Fields[2].At(0))
 		require.Equal(t, "baz1", frame.Fields[2].At(1))
 	})
+
+	t.Run("it back-fills missing map keys with nulls", func(t *testing.T) {
+		maps := []map[string]interface{}{
+			{
+				"a": "foo",
+				"b": int32(36),
+			},
+			{
+				"a": "foo1",
+				"c": true,
+			},
+			{
+				"a": "foo2",
+				"b": int32(38),
+				"c": nil,
+			},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", maps)
+		require.Nil(t, err)
+
+		rows, err := frame.RowLen()
+		require.Nil(t, err)
+		require.Equal(t, 3, rows)
+		require.Len(t, frame.Fields, 3)
+
+		require.Equal(t, "a", frame.Fields[0].Name)
+		require.False(t, frame.Fields[0].Nullable())
+		require.Equal(t, "foo2", frame.Fields[0].At(2))
+
+		require.Equal(t, "b", frame.Fields[1].Name)
+		require.True(t, frame.Fields[1].Nullable())
+		require.Equal(t, int32(36), *frame.Fields[1].At(0).(*int32))
+		require.Nil(t, frame.Fields[1].At(1))
+		require.Equal(t, int32(38), *frame.Fields[1].At(2).(*int32))
+
+		require.Equal(t, "c", frame.Fields[2].Name)
+		require.True(t, frame.Fields[2].Nullable())
+		require.Nil(t, frame.Fields[2].At(0))
+		require.Equal(t, true, *frame.Fields[2].At(1).(*bool))
+		require.Nil(t, frame.Fields[2].At(2))
+	})
+
+	t.Run("it keeps earlier values when a field becomes nullable", func(t *testing.T) {
+		bar := "bar"
+		maps := []map[string]interface{}{
+			{"a": "foo"},
+			{"a": &bar},
+			{"b": "baz"},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", maps)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 3, frame.Fields[0].Len())
+		require.Equal(t, "foo", *frame.Fields[0].At(0).(*string))
+		require.Equal(t, "bar", *frame.Fields[0].At(1).(*string))
+		require.Nil(t, frame.Fields[0].At(2))
+		require.Equal(t, 3, frame.Fields[1].Len())
+	})
+
+	t.Run("it returns an error when map values change type between rows", func(t *testing.T) {
+		maps := []map[string]interface{}{
+			{"a": "foo"},
+			{"a": int32(36)},
+		}
+
+		_, err := framestruct.ToDataFrame("results", maps)
+		require.Error(t, err)
+		require.Equal(t, "mismatched types in a: string and int32", err.Error())
+	})
 }
 
 func TestMaps(t *testing.T) {
@@ -377,37 +615,134 @@ func TestMaps(t *testing.T) {
 	t.Run("it returns an error when any map contains an unsupported type", func(t *testing.T) {
 		m := map[string]interface{}{
 			"Thing1": "foo",
-			"Thing2": int(36),
+			"Thing2": complex64(36),
 			"Thing3": "baz",
 			"Thing4": map[string]interface{}{
-				"Thing5": 37,
+				"Thing5": complex64(37),
 			},
 		}
 
 		_, err := framestruct.ToDataFrame("results", m)
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
 
 		_, err = framestruct.ToDataFrame("results", []map[string]interface{}{m})
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
 	})
 
 	t.Run("it returns an error when any map contains a struct with an unsupported type", func(t *testing.T) {
 		m := map[string]interface{}{
 			"Thing1": "foo",
-			"Thing2": int(36),
+			"Thing2": complex64(36),
 			"Thing3": "baz",
 			"Thing4": unsupportedType{36},
 		}
 
 		_, err := framestruct.ToDataFrame("results", m)
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
 
 		_, err = framestruct.ToDataFrame("results", []map[string]interface{}{m})
 		require.Error(t, err)
-		require.Equal(t, "unsupported type int", err.Error())
+		require.Equal(t, "unsupported type complex64", err.Error())
+	})
+
+	t.Run("it flattens typed maps", func(t *testing.T) {
+		latency := 1.5
+		strct := typedMaps{
+			Tags:      map[status]string{"env": "prod"},
+			Latencies: map[string]*float64{"p50": &latency, "p99": nil},
+			Nested:    map[string]nested3{"a": {true, 100}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Latencies.p50", "Nested.a.Thing7", "Nested.a.Thing8", "Tags.env"}, fieldNames(frame))
+		require.Equal(t, 1.5, frame.Fields[0].At(0))
+		require.Equal(t, int64(100), frame.Fields[2].At(0))
+		require.Equal(t, "prod", frame.Fields[3].At(0))
+
+		rows := []map[string]float64{
+			{"a": 1, "b": 2},
+			{"a": 3},
+		}
+		frame, err = framestruct.ToDataFrame("results", rows)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"a", "b"}, fieldNames(frame))
+		require.Equal(t, 3.0, frame.Fields[0].At(1))
+		require.Nil(t, frame.Fields[1].At(1))
+	})
+
+	t.Run("it flattens maps with integer keys in numeric order", func(t *testing.T) {
+		strct := histogram{
+			Name:    "latency",
+			Buckets: map[int]float64{10: 3, 2: 2, 1: 1, -1: 0},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+		require.Equal(t, []string{"Buckets.-1", "Buckets.1", "Buckets.2", "Buckets.10", "Name"}, fieldNames(frame))
+
+		frame, err = framestruct.ToDataFrame("results", strct, framestruct.WithMapKeyOrder(framestruct.SortMapKeys))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Name", "Buckets.-1", "Buckets.1", "Buckets.2", "Buckets.10"}, fieldNames(frame))
+	})
+
+	t.Run("it sorts structs under integer map keys in numeric order", func(t *testing.T) {
+		strct := stats{
+			Name:    "latency",
+			Buckets: map[int]nested3{10: {true, 3}, 2: {false, 2}, 1: {true, 1}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"Buckets.1.Thing7",
+			"Buckets.1.Thing8",
+			"Buckets.2.Thing7",
+			"Buckets.2.Thing8",
+			"Buckets.10.Thing7",
+			"Buckets.10.Thing8",
+			"Name",
+		}, fieldNames(frame))
+	})
+
+	t.Run("it sorts integer map keys with any separator or field namer", func(t *testing.T) {
+		strct := histogram{
+			Name:    "latency",
+			Buckets: map[int]float64{10: 3, 2: 2, 1: 1, -1: 0},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct, framestruct.WithSeparator(""))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Buckets-1", "Buckets1", "Buckets2", "Buckets10", "Name"}, fieldNames(frame))
+
+		namer := func(path []string) string { return strings.Join(path, "[") }
+		frame, err = framestruct.ToDataFrame("results", strct, framestruct.WithSeparator("/"), framestruct.WithFieldNamer(namer))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Buckets[-1", "Buckets[1", "Buckets[2", "Buckets[10", "Name"}, fieldNames(frame))
+	})
+
+	t.Run("it names map keys with encoding.TextMarshaler or fmt.Stringer", func(t *testing.T) {
+		strct := stringerKeys{
+			Regions: map[zone]nested3{usEast: {true, 1}, euWest: {false, 2}},
+			Points:  map[point]int64{{1, 2}: 3},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"Points.1:2",
+			"Regions.eu-west.Thing7",
+			"Regions.eu-west.Thing8",
+			"Regions.us-east.Thing7",
+			"Regions.us-east.Thing8",
+		}, fieldNames(frame))
+		require.Equal(t, int64(3), frame.Fields[0].At(0))
+		require.Equal(t, int64(2), frame.Fields[2].At(0))
 	})
 
 	t.Run("it can't convert a map that contains a slice", func(t *testing.T) {
@@ -420,6 +755,144 @@ func TestMaps(t *testing.T) {
 	})
 }
 
+func TestScalarSlices(t *testing.T) {
+	t.Run("it explodes slices into a row per element", func(t *testing.T) {
+		strcts := []explodedSlice{
+			{"foo", []string{"a", "b"}},
+			{"bar", []string{"c"}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 3, frame.Fields[0].Len())
+		require.Equal(t, 3, frame.Fields[1].Len())
+
+		require.Equal(t, "foo", frame.Fields[0].At(0))
+		require.Equal(t, "foo", frame.Fields[0].At(1))
+		require.Equal(t, "bar", frame.Fields[0].At(2))
+
+		require.Equal(t, "Tags", frame.Fields[1].Name)
+		require.Equal(t, "a", frame.Fields[1].At(0))
+		require.Equal(t, "b", frame.Fields[1].At(1))
+		require.Equal(t, "c", frame.Fields[1].At(2))
+	})
+
+	t.Run("it keeps exploded slices in declaration order", func(t *testing.T) {
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+		strct := explodedBetween{
+			Name:   "foo",
+			Tags:   []string{"a", "b"},
+			When:   tme,
+			Things: []nested3{{true, 100}},
+			After:  "bar",
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Name", "Tags", "When", "Things.Thing7", "Things.Thing8", "After"}, fieldNames(frame))
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Equal(t, "b", frame.Fields[1].At(1))
+		require.Equal(t, "bar", frame.Fields[5].At(1))
+	})
+
+	t.Run("it takes the cartesian product of exploded slices", func(t *testing.T) {
+		strct := multipleExplodedSlices{
+			[]string{"a", "b"},
+			[]int64{1, 2, 3},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 6, frame.Fields[0].Len())
+		require.Equal(t, 6, frame.Fields[1].Len())
+
+		require.Equal(t, "a", frame.Fields[0].At(0))
+		require.Equal(t, int64(1), frame.Fields[1].At(0))
+		require.Equal(t, "a", frame.Fields[0].At(2))
+		require.Equal(t, int64(3), frame.Fields[1].At(2))
+		require.Equal(t, "b", frame.Fields[0].At(5))
+		require.Equal(t, int64(3), frame.Fields[1].At(5))
+	})
+
+	t.Run("it keeps the row when an exploded slice is empty", func(t *testing.T) {
+		strcts := []explodedSlice{
+			{"foo", []string{"a"}},
+			{"bar", nil},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, "bar", frame.Fields[0].At(1))
+		require.Nil(t, frame.Fields[1].At(1))
+	})
+
+	t.Run("it joins slices into a delimited string", func(t *testing.T) {
+		strct := joinedSlices{
+			[]string{"a", "b", "c"},
+			[]float64{1.5, 2},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, "Tags", frame.Fields[0].Name)
+		require.Equal(t, "a,b,c", frame.Fields[0].At(0))
+		require.Equal(t, "Values", frame.Fields[1].Name)
+		require.Equal(t, "1.5|2", frame.Fields[1].At(0))
+	})
+
+	t.Run("it joins and indexes slices of named times", func(t *testing.T) {
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+		strct := namedTimeSlices{
+			Joined:  []namedTime{namedTime(tme), namedTime(tme.Add(time.Minute))},
+			Indexed: []namedTime{namedTime(tme)},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Joined", "Indexed.0"}, fieldNames(frame))
+		require.Equal(t, 1, frame.Fields[0].Len())
+		require.Equal(t, "2009-11-17T20:34:58Z,2009-11-17T20:35:58Z", frame.Fields[0].At(0))
+		require.Equal(t, tme, frame.Fields[1].At(0))
+	})
+
+	t.Run("it creates a field for each index", func(t *testing.T) {
+		strcts := []indexedSlice{
+			{[]string{"a", "b"}},
+			{[]string{"c"}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, "Tags.0", frame.Fields[0].Name)
+		require.Equal(t, "a", frame.Fields[0].At(0))
+		require.Equal(t, "c", frame.Fields[0].At(1))
+
+		require.Equal(t, "Tags.1", frame.Fields[1].Name)
+		require.Equal(t, "b", *frame.Fields[1].At(0).(*string))
+		require.Nil(t, frame.Fields[1].At(1))
+	})
+
+	t.Run("it returns an error for unknown slice options", func(t *testing.T) {
+		strct := unknownSliceOption{[]string{"a"}}
+
+		_, err := framestruct.ToDataFrame("results", strct)
+		require.Error(t, err)
+		require.Equal(t, `unsupported slice option "zip"`, err.Error())
+	})
+}
+
 func TestStructTags(t *testing.T) {
 	t.Run("it ignores fields when the struct tag is a '-'", func(t *testing.T) {
 		strct := structWithIgnoredTag{"foo", "bar", "baz"}
@@ -589,13 +1062,82 @@ func TestStructTags(t *testing.T) {
 		require.Equal(t, "Thing6", frame.Fields[3].Name)
 		require.Equal(t, int64(100), frame.Fields[3].At(0))
 	})
+
+	t.Run("it accepts options in any order", func(t *testing.T) {
+		strct := reorderedTags{
+			Foo: barBazReordered{
+				Bar: "should be first",
+				Baz: map[string]interface{}{"aaa": "foo"},
+			},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+		require.Equal(t, []string{"zzz", "aaa"}, fieldNames(frame))
+	})
+
+	t.Run("it reads quoted names and values", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", quotedTags{1.5})
+		require.Nil(t, err)
+
+		require.Equal(t, "latency, ms", frame.Fields[0].Name)
+		require.Equal(t, "Latency=p99, it's slow", frame.Fields[0].Config.DisplayName)
+	})
+
+	t.Run("it reads apostrophes in unquoted names and values", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", apostropheTags{"foo", 1.5})
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"it's", "rock'n'roll"}, fieldNames(frame))
+		require.Equal(t, "Latency, it's slow", frame.Fields[1].Config.DisplayName)
+	})
+
+	t.Run("it puts fields with an order first, sorted by it", func(t *testing.T) {
+		strct := orderedTags{
+			Zed:   "zed",
+			Foo:   map[string]interface{}{"aaa": "foo"},
+			Alpha: "alpha",
+			Beta:  "beta",
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+		require.Equal(t, []string{"Alpha", "Zed", "Beta", "Foo.aaa"}, fieldNames(frame))
+	})
+
+	t.Run("it returns an error when a tag is malformed", func(t *testing.T) {
+		_, err := framestruct.ToDataFrame("results", unknownOption{"foo"})
+		require.Error(t, err)
+		require.Equal(t, `invalid frame tag on Foo: unknown option "omitparents"`, err.Error())
+
+		_, err = framestruct.ToDataFrame("results", []unknownKey{{"foo"}})
+		require.Error(t, err)
+		require.Equal(t, `invalid frame tag on Foo: unknown option "units"`, err.Error())
+
+		_, err = framestruct.ToDataFrame("results", unterminatedQuote{"foo"})
+		require.Error(t, err)
+		require.Equal(t, `invalid frame tag on Foo: unterminated quote in "'foo,bar"`, err.Error())
+
+		_, err = framestruct.ToDataFrame("results", invalidOrder{"foo"})
+		require.Error(t, err)
+		require.Equal(t, `invalid frame tag on Foo: order must be an integer: "first"`, err.Error())
+
+		_, err = framestruct.NewFrameBuilder("results", unknownOption{})
+		require.Error(t, err)
+
+		var out unknownOption
+		frame, err := framestruct.ToDataFrame("results", simpleStruct{"foo", 36, "baz"})
+		require.Nil(t, err)
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Error(t, err)
+	})
 }
 func TestToDataframe(t *testing.T) {
 	t.Run("it returns an error when invalid types are passed in", func(t *testing.T) {
-		_, err := framestruct.ToDataFrame("???", []string{"1", "2"})
+		_, err := framestruct.ToDataFrame("???", [][]string{{"1", "2"}})
 		require.Error(t, err)
 
-		m := make(map[string]string)
+		m := make(map[float64]string)
 		_, err = framestruct.ToDataFrame("???", m)
 		require.Error(t, err)
 
@@ -605,10 +1147,33 @@ func TestToDataframe(t *testing.T) {
 		_, err = framestruct.ToDataFrame("???", time.Now())
 		require.Error(t, err)
 
-		_, err = framestruct.ToDataFrame("???", []time.Time{time.Now()})
+		_, err = framestruct.ToDataFrame("???", []complex64{1})
 		require.Error(t, err)
 	})
 
+	t.Run("it converts a slice of values into a single column", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", []float64{1.5, 2.5})
+		require.Nil(t, err)
+		require.Equal(t, []string{"results"}, fieldNames(frame))
+		require.Equal(t, 2.5, frame.Fields[0].At(1))
+
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+		frame, err = framestruct.ToDataFrame("results", []*time.Time{&tme, nil}, framestruct.WithColumnName("time"))
+		require.Nil(t, err)
+		require.Equal(t, []string{"time"}, fieldNames(frame))
+		require.Equal(t, &tme, frame.Fields[0].At(0))
+		require.Nil(t, frame.Fields[0].At(1))
+
+		frame, err = framestruct.ToDataFrame("results", []celsius{20.5})
+		require.Nil(t, err)
+		require.Equal(t, 20.5, frame.Fields[0].At(0))
+
+		frame, err = framestruct.Convert("results", []string{"a", "b"})
+		require.Nil(t, err)
+		require.Equal(t, []string{"results"}, fieldNames(frame))
+		require.Equal(t, "b", frame.Fields[0].At(1))
+	})
+
 	// This test fails when run with -race when it's not threadsafe
 	t.Run("it is threadsafe", func(t *testing.T) {
 		start := make(chan struct{})
@@ -711,6 +1276,16 @@ type nested3 struct {
 	Thing8 int64
 }
 
+type pointerSliceStruct struct {
+	Name   string
+	Things []*nested3
+}
+
+type repeatedNested struct {
+	First  nested3
+	Second nested3
+}
+
 type structWithTags struct {
 	Thing1 string  `frame:"first-thing"`
 	Thing2 string  `frame:"second-thing"`
@@ -736,13 +1311,73 @@ type supportedWithUnsupported struct {
 }
 
 type unsupportedType struct {
-	Foo int
+	Foo complex64
 }
 
 type unsupportedTypeSlice struct {
 	Foo []string
 }
 
+type explodedBetween struct {
+	Name   string
+	Tags   []string `frame:",slice=explode"`
+	When   time.Time
+	Things []nested3
+	After  string
+}
+
+type explodedSlice struct {
+	Name string
+	Tags []string `frame:",slice=explode"`
+}
+
+type multipleExplodedSlices struct {
+	Tags   []string `frame:",slice=explode"`
+	Values []int64  `frame:",slice=explode"`
+}
+
+type namedTimeSlices struct {
+	Joined  []namedTime `frame:",slice=join"`
+	Indexed []namedTime `frame:",slice=index"`
+}
+
+type joinedSlices struct {
+	Tags   []string  `frame:",slice=join"`
+	Values []float64 `frame:",slice=join,delim=|"`
+}
+
+type indexedSlice struct {
+	Tags []string `frame:",slice=index"`
+}
+
+type unknownSliceOption struct {
+	Tags []string `frame:",slice=zip"`
+}
+
+type intStruct struct {
+	Int     int
+	Uint    uint
+	Uintptr uintptr
+	IntP    *int
+	UintP   *uint
+}
+
+type interfaceStruct struct {
+	Value interface{}
+}
+
+type celsius float64
+type status string
+type code int32
+type namedTime time.Time
+
+type namedTypes struct {
+	Temp   celsius
+	Status status
+	Code   code
+	Time   namedTime
+}
+
 type pointerStruct struct {
 	Foo *string
 }
@@ -751,11 +1386,100 @@ type structWithMap struct {
 	Foo map[string]interface{}
 }
 
+type typedMaps struct {
+	Tags      map[status]string
+	Latencies map[string]*float64
+	Nested    map[string]nested3
+}
+
+type stats struct {
+	Name    string
+	Buckets map[int]nested3
+}
+
+type histogram struct {
+	Name    string
+	Buckets map[int]float64
+}
+
+type zone int
+
+const (
+	usEast zone = iota
+	euWest
+)
+
+func (r zone) String() string {
+	if r == usEast {
+		return "us-east"
+	}
+	return "eu-west"
+}
+
+type point struct {
+	X, Y int
+}
+
+func (p *point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d:%d", p.X, p.Y)), nil
+}
+
+func (p *point) UnmarshalText(b []byte) error {
+	_, err := fmt.Sscanf(string(b), "%d:%d", &p.X, &p.Y)
+	return err
+}
+
+type stringerKeys struct {
+	Regions map[zone]nested3
+	Points  map[point]int64
+}
+
 type structWithCol0 struct {
 	Zed string                 `frame:"zzz,,col0"`
 	Foo map[string]interface{} `frame:",omitparent"`
 }
 
+type reorderedTags struct {
+	Foo barBazReordered
+}
+
+type barBazReordered struct {
+	Bar string                 `frame:"zzz,col0,omitparent"`
+	Baz map[string]interface{} `frame:",omitparent"`
+}
+
+type quotedTags struct {
+	Latency float64 `frame:"'latency, ms',displayName='Latency=p99, it''s slow'"`
+}
+
+type orderedTags struct {
+	Zed   string `frame:",order=2"`
+	Foo   map[string]interface{}
+	Alpha string `frame:",order=1"`
+	Beta  string
+}
+
+type invalidOrder struct {
+	Foo string `frame:"foo,order=first"`
+}
+
+type apostropheTags struct {
+	A string  `frame:"it's"`
+	B float64 `frame:"rock'n'roll,displayName='Latency, it''s slow'"`
+}
+
+type unknownOption struct {
+	Foo string `frame:"foo,omitparents"`
+}
+
+type unknownKey struct {
+	Foo string `frame:"foo,units=ms"`
+}
+
+type unterminatedQuote struct {
+	Foo string `frame:"'foo,bar"`
+}
+
 type allStructTags struct {
 	Foo barBaz
 }
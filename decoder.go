@@ -0,0 +1,248 @@
+package framestruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+type decoder struct {
+	*converter
+	fields  map[string]*data.Field
+	labels  data.Labels
+	claimed map[string]bool
+}
+
+// FromDataFrame populates out with the contents of a *data.Frame. out must be
+// a pointer to a struct, a map, or a slice of either. When
+// out points to a slice, it is replaced with one element per row of the frame.
+// Otherwise, out is populated from the first row.
+//
+// Columns are matched to struct fields using the same names and frame tags as
+// ToDataFrame, so a frame produced by ToDataFrame converts back into the
+// values it was created from. Columns without a matching field are ignored
+// unless they can be placed in a map field. Options that affect naming, such
+// as WithSeparator and WithTagKey, should match the ones used to create the
+// frame.
+func FromDataFrame(frame *data.Frame, out interface{}, opts ...Option) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("out must be a non-nil pointer")
+	}
+
+	d := &decoder{
+		converter: newConverter(opts...),
+		fields:    make(map[string]*data.Field),
+		labels:    make(data.Labels),
+	}
+	for _, f := range frame.Fields {
+		if _, exists := d.fields[f.Name]; !exists {
+			d.fields[f.Name] = f
+		}
+		for name, value := range f.Labels {
+			d.labels[name] = value
+		}
+	}
+
+	return d.decode(v.Elem(), frame.Rows())
+}
+
+func (d *decoder) decode(v reflect.Value, rows int) error {
+	if !supportedDecodeType(v.Type()) {
+		return errors.New("unsupported type: can only decode into structs, slices, and maps")
+	}
+
+	d.claimed = make(map[string]bool)
+	t := v.Type()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if err := d.claimFields(t, nil); err != nil {
+		return err
+	}
+
+	if v.Kind() != reflect.Slice {
+		if rows == 0 {
+			return errors.New("frame has no rows")
+		}
+		return d.decodeRow(v, 0)
+	}
+
+	s := reflect.MakeSlice(v.Type(), rows, rows)
+	for i := 0; i < rows; i++ {
+		if err := d.decodeRow(s.Index(i), i); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+
+	return nil
+}
+
+func (d *decoder) decodeRow(v reflect.Value, row int) error {
+	if v.Kind() == reflect.Map {
+		return d.decodeMap(v, row, fieldTags{}, nil)
+	}
+	return d.decodeStruct(v, row, nil)
+}
+
+// claimFields records every column name that belongs to a struct field
+// so that maps only receive the columns nothing else has asked for. It
+// returns an error when the tags of any struct it visits are invalid.
+func (d *decoder) claimFields(t reflect.Type, path []string) error {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	info := cachedStructInfo(t, d.tagKey)
+	if info.err != nil {
+		return info.err
+	}
+
+	for _, fi := range info.fields {
+		fieldPath := d.fieldPath(d.structFieldName(fi.name), fi.tags, path)
+		switch {
+		case isScanner(fi.typ), d.storesText(fi.typ, fi.tags.asString):
+			d.claimed[d.columnName(fieldPath)] = true
+		case isNestedStruct(fi.typ):
+			if err := d.claimFields(fi.typ, fieldPath); err != nil {
+				return err
+			}
+		case fi.tags.slice == sliceIndex:
+			for i := 0; ; i++ {
+				name := d.columnName(d.fieldPath(strconv.Itoa(i), fieldTags{}, fieldPath))
+				if d.fields[name] == nil {
+					break
+				}
+				d.claimed[name] = true
+			}
+		case fi.typ.Kind() != reflect.Map:
+			d.claimed[d.columnName(fieldPath)] = true
+		}
+	}
+	return nil
+}
+
+func (d *decoder) decodeStruct(v reflect.Value, row int, path []string) error {
+	info := cachedStructInfo(v.Type(), d.tagKey)
+	for _, fi := range info.fields {
+		field := v.Field(fi.index)
+		fieldPath := d.fieldPath(d.structFieldName(fi.name), fi.tags, path)
+		var err error
+		switch {
+		case fi.tags.label && field.Kind() == reflect.Map:
+			err = d.decodeLabels(field)
+		case fi.tags.label:
+			err = d.decodeLabel(field, d.columnName(fieldPath))
+		case isScanner(field.Type()):
+			err = d.decodeScanner(field, row, d.columnName(fieldPath))
+		case d.storesText(field.Type(), fi.tags.asString):
+			err = d.decodeText(field, row, d.columnName(fieldPath))
+		case isNestedStruct(field.Type()):
+			err = d.decodeStruct(field, row, fieldPath)
+		case field.Kind() == reflect.Map:
+			err = d.decodeMap(field, row, fi.tags, fieldPath)
+		case field.Kind() == reflect.Slice && isScalarSlice(field.Type()):
+			err = d.decodeScalarSlice(field, row, fi.tags, fieldPath)
+		default:
+			err = d.decodeField(field, row, d.columnName(fieldPath))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) decodeMap(v reflect.Value, row int, tags fieldTags, path []string) error {
+	t := v.Type()
+	if !supportedDecodeKeyType(t.Key()) {
+		return fmt.Errorf("unsupported type %s: map keys must be strings, integers, or implement encoding.TextUnmarshaler", t)
+	}
+
+	prefix := ""
+	if !tags.omitParent && len(path) > 0 {
+		prefix = d.columnName(path) + d.separator
+	}
+
+	m := reflect.MakeMap(t)
+	for name, f := range d.fields {
+		if d.claimed[name] || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		val, ok := f.ConcreteAt(row)
+		if !ok {
+			continue
+		}
+
+		elem := reflect.New(t.Elem()).Elem()
+		if err := assign(elem, reflect.ValueOf(val)); err != nil {
+			return fmt.Errorf("unable to decode %s: %w", name, err)
+		}
+		key, err := parseMapKey(strings.TrimPrefix(name, prefix), t.Key())
+		if err != nil {
+			return fmt.Errorf("unable to decode %s: %w", name, err)
+		}
+		m.SetMapIndex(key, elem)
+	}
+
+	if m.Len() > 0 {
+		v.Set(m)
+	}
+	return nil
+}
+
+func (d *decoder) decodeField(v reflect.Value, row int, fieldName string) error {
+	f, ok := d.fields[fieldName]
+	if !ok {
+		return nil
+	}
+
+	if err := assign(v, reflect.ValueOf(f.At(row))); err != nil {
+		return fmt.Errorf("unable to decode %s: %w", fieldName, err)
+	}
+	return nil
+}
+
+// assign sets dst to src, dereferencing or taking the address of src
+// when the column and the struct field disagree about nullability, and
+// converting src when the field is a named or narrower type
+func assign(dst, src reflect.Value) error {
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+	case src.Kind() == reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		return assign(dst, src.Elem())
+	case dst.Kind() == reflect.Ptr:
+		p := reflect.New(dst.Type().Elem())
+		if err := assign(p.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(p)
+	case fieldType(dst.Type()) == src.Type():
+		dst.Set(src.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("%s is not assignable to %s", src.Type(), dst.Type())
+	}
+	return nil
+}
+
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && fieldType(t) == nil
+}
+
+func supportedDecodeType(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return isNestedStruct(t) || t.Kind() == reflect.Map && supportedDecodeKeyType(t.Key())
+}
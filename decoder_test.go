@@ -0,0 +1,312 @@
+package framestruct_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromDataFrame(t *testing.T) {
+	t.Run("it populates a struct from the first row", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing1", nil, []string{"foo", "foo1"}),
+			data.NewField("Thing2", nil, []int32{36, 37}),
+			data.NewField("Thing3", nil, []string{"baz", "baz1"}),
+		)
+
+		var strct simpleStruct
+		err := framestruct.FromDataFrame(frame, &strct)
+		require.Nil(t, err)
+
+		require.Equal(t, simpleStruct{"foo", 36, "baz"}, strct)
+	})
+
+	t.Run("it populates a slice of structs", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing1", nil, []string{"foo", "foo1"}),
+			data.NewField("Thing2", nil, []int32{36, 37}),
+			data.NewField("Thing3", nil, []string{"baz", "baz1"}),
+		)
+
+		var strcts []simpleStruct
+		err := framestruct.FromDataFrame(frame, &strcts)
+		require.Nil(t, err)
+
+		require.Equal(t, []simpleStruct{
+			{"foo", 36, "baz"},
+			{"foo1", 37, "baz1"},
+		}, strcts)
+	})
+
+	t.Run("it ignores columns without a matching field", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing1", nil, []string{"foo"}),
+			data.NewField("Unknown", nil, []string{"bar"}),
+		)
+
+		var strct simpleStruct
+		err := framestruct.FromDataFrame(frame, &strct)
+		require.Nil(t, err)
+
+		require.Equal(t, simpleStruct{Thing1: "foo"}, strct)
+	})
+
+	t.Run("it converts between nullable and non-nullable columns", func(t *testing.T) {
+		foo := "foo"
+		frame := data.NewFrame("results",
+			data.NewField("Foo", nil, []string{foo}),
+		)
+
+		var ptr pointerStruct
+		err := framestruct.FromDataFrame(frame, &ptr)
+		require.Nil(t, err)
+		require.Equal(t, "foo", *ptr.Foo)
+
+		frame = data.NewFrame("results",
+			data.NewField("Thing1", nil, []*string{&foo, nil}),
+		)
+
+		var strcts []simpleStruct
+		err = framestruct.FromDataFrame(frame, &strcts)
+		require.Nil(t, err)
+		require.Equal(t, "foo", strcts[0].Thing1)
+		require.Equal(t, "", strcts[1].Thing1)
+	})
+
+	t.Run("it returns an error when a column doesn't match the field type", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing2", nil, []string{"foo"}),
+		)
+
+		var strct simpleStruct
+		err := framestruct.FromDataFrame(frame, &strct)
+		require.Error(t, err)
+		require.Equal(t, "unable to decode Thing2: string is not assignable to int32", err.Error())
+	})
+
+	t.Run("it returns an error when out is not a pointer", func(t *testing.T) {
+		frame := data.NewFrame("results")
+
+		err := framestruct.FromDataFrame(frame, simpleStruct{})
+		require.Error(t, err)
+	})
+
+	t.Run("it returns an error when out is an unsupported type", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing1", nil, []string{"foo"}),
+		)
+
+		var s []string
+		err := framestruct.FromDataFrame(frame, &s)
+		require.Error(t, err)
+	})
+
+	t.Run("it returns an error when populating a struct from an empty frame", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing1", nil, []string{}),
+		)
+
+		var strct simpleStruct
+		err := framestruct.FromDataFrame(frame, &strct)
+		require.Error(t, err)
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Run("it round trips nested structs", func(t *testing.T) {
+		strcts := []nested1{
+			{"foo", 36, "baz", nested3{true, 100}},
+			{"foo1", 37, "baz1", nested3{false, 101}},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		var out []nested1
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, strcts, out)
+	})
+
+	t.Run("it round trips struct tags", func(t *testing.T) {
+		strct := omitParentStruct{
+			"foo",
+			"bar",
+			nested2{true, 100},
+			nested3{false, 200},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		var out omitParentStruct
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, strct, out)
+	})
+
+	t.Run("it skips ignored fields", func(t *testing.T) {
+		strct := structWithIgnoredTag{"foo", "bar", "baz"}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		var out structWithIgnoredTag
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, structWithIgnoredTag{"foo", "", "baz"}, out)
+	})
+
+	t.Run("it round trips times and pointers", func(t *testing.T) {
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+
+		frame, err := framestruct.ToDataFrame("results", timePointerStruct{&tme})
+		require.Nil(t, err)
+
+		var out timePointerStruct
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, tme, *out.Time)
+	})
+
+	t.Run("it round trips maps in structs", func(t *testing.T) {
+		strct := allStructTags{
+			Foo: barBaz{
+				Bar: "should be first",
+				Baz: map[string]interface{}{
+					"aaa": "foo",
+					"bbb": "foo",
+					"ccc": "foo",
+				},
+			},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		var out allStructTags
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, strct, out)
+
+		m := structWithMap{
+			map[string]interface{}{
+				"Thing1": "foo",
+			},
+		}
+
+		frame, err = framestruct.ToDataFrame("results", m)
+		require.Nil(t, err)
+
+		var outMap structWithMap
+		err = framestruct.FromDataFrame(frame, &outMap)
+		require.Nil(t, err)
+		require.Equal(t, m, outMap)
+	})
+
+	t.Run("it round trips slices of maps", func(t *testing.T) {
+		maps := []map[string]interface{}{
+			{"Thing1": "foo", "Thing2": int32(36)},
+			{"Thing1": "foo1", "Thing2": int32(37)},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", maps)
+		require.Nil(t, err)
+
+		var out []map[string]interface{}
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, maps, out)
+	})
+
+	t.Run("it round trips typed maps", func(t *testing.T) {
+		rows := []map[string]float64{
+			{"a": 1, "b": 2},
+			{"a": 3, "b": 4},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows)
+		require.Nil(t, err)
+
+		var out []map[string]float64
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, rows, out)
+
+		var outStrings []map[string]string
+		err = framestruct.FromDataFrame(frame, &outStrings)
+		require.Error(t, err)
+	})
+
+	t.Run("it round trips maps with integer and text keys", func(t *testing.T) {
+		strct := histogram{
+			Name:    "latency",
+			Buckets: map[int]float64{10: 3, 2: 2, -1: 0},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		var out histogram
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, strct, out)
+
+		points := map[point]int64{{1, 2}: 3}
+		frame, err = framestruct.ToDataFrame("results", points)
+		require.Nil(t, err)
+
+		var outPoints map[point]int64
+		err = framestruct.FromDataFrame(frame, &outPoints)
+		require.Nil(t, err)
+		require.Equal(t, points, outPoints)
+
+		var outZones map[zone]interface{}
+		err = framestruct.FromDataFrame(frame, &outZones)
+		require.Error(t, err)
+	})
+
+	t.Run("it round trips joined and indexed slices", func(t *testing.T) {
+		joined := joinedSlices{
+			[]string{"a", "b", "c"},
+			[]float64{1.5, 2},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", joined)
+		require.Nil(t, err)
+
+		var outJoined joinedSlices
+		err = framestruct.FromDataFrame(frame, &outJoined)
+		require.Nil(t, err)
+		require.Equal(t, joined, outJoined)
+
+		indexed := []indexedSlice{
+			{[]string{"a", "b"}},
+			{[]string{"c"}},
+		}
+
+		frame, err = framestruct.ToDataFrame("results", indexed)
+		require.Nil(t, err)
+
+		var outIndexed []indexedSlice
+		err = framestruct.FromDataFrame(frame, &outIndexed)
+		require.Nil(t, err)
+		require.Equal(t, indexed, outIndexed)
+	})
+
+	t.Run("it decodes exploded slices into an element per row", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", explodedSlice{"foo", []string{"a", "b"}})
+		require.Nil(t, err)
+
+		var out []explodedSlice
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, []explodedSlice{
+			{"foo", []string{"a"}},
+			{"foo", []string{"b"}},
+		}, out)
+	})
+}
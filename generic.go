@@ -0,0 +1,47 @@
+package framestruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Convert is the type-safe version of ToDataFrame for slices. Each element of
+// rows becomes a row of the frame. T must be a struct, a map, or a value like
+// a float64, which is stored in a single column. The tags of structs must be
+// valid, which is checked before any rows are converted, even when rows is
+// empty.
+func Convert[T any](name string, rows []T, opts ...Option) (*data.Frame, error) {
+	t := indirectType(typeOf[T]())
+	if !supportedRowType(t) {
+		return nil, fmt.Errorf("unsupported type %s: can only convert structs, maps, and values", t)
+	}
+
+	c := newConverter(opts...)
+	c.nameScalars(name)
+	if isNestedStruct(t) {
+		if _, err := c.plan(t, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.convertRows(reflect.ValueOf(rows)); err != nil {
+		return nil, err
+	}
+	return c.createFrame(name)
+}
+
+// Decode is the type-safe version of FromDataFrame. It returns one T for
+// every row of the frame. T must be a struct or a map.
+func Decode[T any](frame *data.Frame, opts ...Option) ([]T, error) {
+	var rows []T
+	if err := FromDataFrame(frame, &rows, opts...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
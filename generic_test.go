@@ -0,0 +1,92 @@
+package framestruct_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	t.Run("it converts a slice of structs", func(t *testing.T) {
+		strcts := []nested1{
+			{"foo", 36, "baz", nested3{true, 100}},
+			{"foo1", 37, "baz1", nested3{false, 101}},
+		}
+
+		frame, err := framestruct.Convert("results", strcts)
+		require.Nil(t, err)
+
+		expected, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+		require.Equal(t, expected, frame)
+	})
+
+	t.Run("it converts a slice of maps", func(t *testing.T) {
+		maps := []map[string]interface{}{
+			{"Thing1": "foo"},
+			{"Thing1": "foo1"},
+		}
+
+		frame, err := framestruct.Convert("results", maps)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, "foo1", frame.Fields[0].At(1))
+	})
+
+	t.Run("it accepts options", func(t *testing.T) {
+		strcts := []nested1{{"foo", 36, "baz", nested3{true, 100}}}
+
+		frame, err := framestruct.Convert("results", strcts, framestruct.WithSeparator("_"))
+		require.Nil(t, err)
+		require.Equal(t, "Thing4_Thing7", frame.Fields[3].Name)
+	})
+
+	t.Run("it returns an error for unsupported types without any rows", func(t *testing.T) {
+		_, err := framestruct.Convert("results", [][]string{})
+		require.Error(t, err)
+		require.Equal(t, "unsupported type []string: can only convert structs, maps, and values", err.Error())
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("it decodes every row", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing1", nil, []string{"foo", "foo1"}),
+			data.NewField("Thing2", nil, []int32{36, 37}),
+			data.NewField("Thing3", nil, []string{"baz", "baz1"}),
+		)
+
+		strcts, err := framestruct.Decode[simpleStruct](frame)
+		require.Nil(t, err)
+		require.Equal(t, []simpleStruct{
+			{"foo", 36, "baz"},
+			{"foo1", 37, "baz1"},
+		}, strcts)
+	})
+
+	t.Run("it round trips with Convert", func(t *testing.T) {
+		strcts := []nested1{
+			{"foo", 36, "baz", nested3{true, 100}},
+			{"foo1", 37, "baz1", nested3{false, 101}},
+		}
+
+		frame, err := framestruct.Convert("results", strcts)
+		require.Nil(t, err)
+
+		out, err := framestruct.Decode[nested1](frame)
+		require.Nil(t, err)
+		require.Equal(t, strcts, out)
+	})
+
+	t.Run("it returns an error for unsupported types", func(t *testing.T) {
+		frame := data.NewFrame("results",
+			data.NewField("Thing1", nil, []string{"foo"}),
+		)
+
+		_, err := framestruct.Decode[string](frame)
+		require.Error(t, err)
+	})
+}
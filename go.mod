@@ -1,8 +1,25 @@
 module github.com/masslessparticle/go-framestruct
 
-go 1.16
+go 1.18
 
 require (
 	github.com/grafana/grafana-plugin-sdk-go v0.92.0
 	github.com/stretchr/testify v1.7.0
 )
+
+require (
+	github.com/apache/arrow/go/arrow v0.0.0-20210223225224-5bea62493d91 // indirect
+	github.com/cheekybits/genny v1.0.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/flatbuffers v1.11.0 // indirect
+	github.com/google/go-cmp v0.5.5 // indirect
+	github.com/json-iterator/go v1.1.10 // indirect
+	github.com/mattetti/filebuffer v1.0.1 // indirect
+	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.1 // indirect
+	github.com/olekukonko/tablewriter v0.0.5 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776 // indirect
+)
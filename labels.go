@@ -0,0 +1,122 @@
+package framestruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// setLabel adds the value of a field tagged label to the labels of the
+// frame's value fields. Every row must have the same value. Null values are
+// ignored.
+func (c *converter) setLabel(name string, v reflect.Value) error {
+	if isNull(v) {
+		return nil
+	}
+
+	text, ok, err := c.textValue(v, true)
+	if err != nil {
+		return err
+	}
+	if ok {
+		v = text
+	}
+	value := formatElem(c.inLocation(v))
+
+	existing, ok := c.labels[name]
+	switch {
+	case !ok:
+		c.labels[name] = value
+		c.labelNames = append(c.labelNames, name)
+	case existing != value:
+		return fmt.Errorf("label %s must have the same value in every row: %q and %q", name, existing, value)
+	}
+	return nil
+}
+
+// setLabels adds every entry of a map tagged label to the labels of the
+// frame's value fields, using the keys as label names
+func (c *converter) setLabels(m reflect.Value) error {
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return c.unsupported(fmt.Errorf("unsupported type %s: labels must be a map with string keys", m.Type()))
+	}
+
+	iter := m.MapRange()
+	for iter.Next() {
+		value := iter.Value()
+		if value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+
+		if err := c.setLabel(iter.Key().String(), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLabels labels the value fields of frame: the fields tagged value, or
+// every field that isn't a time when no fields are tagged value
+func (c *converter) applyLabels(frame *data.Frame) {
+	if len(c.labels) == 0 {
+		return
+	}
+
+	for _, f := range frame.Fields {
+		if len(c.valueFields) > 0 && !c.valueFields[f.Name] {
+			continue
+		}
+		if len(c.valueFields) == 0 && f.Type().Time() {
+			continue
+		}
+
+		if f.Labels == nil {
+			f.Labels = make(data.Labels, len(c.labels))
+		}
+		for name, value := range c.labels {
+			f.Labels[name] = value
+		}
+	}
+}
+
+// decodeLabel populates a field tagged label from the labels of the frame's
+// fields
+func (d *decoder) decodeLabel(v reflect.Value, name string) error {
+	value, ok := d.labels[name]
+	if !ok {
+		return nil
+	}
+
+	if err := setText(v, value); err != nil {
+		return fmt.Errorf("unable to decode label %s: %w", name, err)
+	}
+	return nil
+}
+
+// decodeLabels populates a map tagged label with every label of the frame's
+// fields
+func (d *decoder) decodeLabels(v reflect.Value) error {
+	t := v.Type()
+	if t.Key().Kind() != reflect.String {
+		return fmt.Errorf("unsupported type %s: labels must be a map with string keys", t)
+	}
+
+	if len(d.labels) == 0 {
+		return nil
+	}
+
+	m := reflect.MakeMapWithSize(t, len(d.labels))
+	for name, value := range d.labels {
+		dst := reflect.New(t.Elem()).Elem()
+		if err := setText(dst, value); err != nil {
+			return fmt.Errorf("unable to decode label %s: %w", name, err)
+		}
+		m.SetMapIndex(reflect.ValueOf(name).Convert(t.Key()), dst)
+	}
+	v.Set(m)
+	return nil
+}
@@ -0,0 +1,81 @@
+package framestruct_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	tme := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+
+	t.Run("it labels value fields with fields tagged label", func(t *testing.T) {
+		rows := []labelledMetric{
+			{tme, "a", 2, map[string]string{"env": "prod"}, 0.5},
+			{tme.Add(time.Minute), "a", 2, map[string]string{"env": "prod"}, 0.75},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"time", "cpu"}, fieldNames(frame))
+		require.Nil(t, frame.Fields[0].Labels)
+		require.Equal(t, data.Labels{"host": "a", "shard": "2", "env": "prod"}, frame.Fields[1].Labels)
+		require.Equal(t, 0.75, frame.Fields[1].At(1))
+	})
+
+	t.Run("it only labels fields tagged value when there are any", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", taggedValues{"a", 1, 2})
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Count", "Total"}, fieldNames(frame))
+		require.Nil(t, frame.Fields[0].Labels)
+		require.Equal(t, data.Labels{"Host": "a"}, frame.Fields[1].Labels)
+	})
+
+	t.Run("it returns an error when a label changes between rows", func(t *testing.T) {
+		rows := []labelledMetric{
+			{tme, "a", 2, nil, 0.5},
+			{tme, "b", 2, nil, 0.75},
+		}
+
+		_, err := framestruct.ToDataFrame("results", rows)
+		require.Error(t, err)
+		require.Equal(t, `label host must have the same value in every row: "a" and "b"`, err.Error())
+	})
+
+	t.Run("it round trips labels", func(t *testing.T) {
+		rows := []labelledMetric{
+			{tme, "a", 2, map[string]string{"env": "prod"}, 0.5},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows)
+		require.Nil(t, err)
+
+		var out []labelledMetric
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+
+		require.Equal(t, "a", out[0].Host)
+		require.Equal(t, 2, out[0].Shard)
+		require.Equal(t, "prod", out[0].Extra["env"])
+		require.Equal(t, 0.5, out[0].CPU)
+	})
+}
+
+type labelledMetric struct {
+	Time  time.Time         `frame:"time"`
+	Host  string            `frame:"host,label"`
+	Shard int               `frame:"shard,label"`
+	Extra map[string]string `frame:",label"`
+	CPU   float64           `frame:"cpu"`
+}
+
+type taggedValues struct {
+	Host  string `frame:",label"`
+	Count int64
+	Total int64 `frame:",value"`
+}
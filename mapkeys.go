@@ -0,0 +1,170 @@
+package framestruct
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mapKey is a key of a map and the name of its column
+type mapKey struct {
+	value reflect.Value
+	name  string
+	// sort is the name zero-padded so integer keys sort numerically
+	sort string
+}
+
+// supportedKeyType reports whether maps with keys of type t can be
+// converted
+func supportedKeyType(t reflect.Type) bool {
+	return t.Kind() == reflect.String ||
+		implements(t, textMarshalerType) ||
+		implements(t, stringerType) ||
+		isInteger(t.Kind())
+}
+
+// supportedDecodeKeyType reports whether maps with keys of type t can be
+// decoded
+func supportedDecodeKeyType(t reflect.Type) bool {
+	return t.Kind() == reflect.String ||
+		implements(t, textUnmarshalerType) ||
+		isInteger(t.Kind()) && !implements(t, textMarshalerType) && !implements(t, stringerType)
+}
+
+func isInteger(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// mapKeys returns the keys of m, sorted if the MapKeyOrder calls for it.
+// Strings are their own names. Other keys are named with
+// encoding.TextMarshaler or fmt.Stringer, or formatted if they're integers.
+func (c *converter) mapKeys(m reflect.Value) ([]mapKey, error) {
+	keys := make([]mapKey, 0, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		key, err := newMapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if c.mapKeyOrder == SortMapKeys {
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].sort < keys[j].sort
+		})
+	}
+	return keys, nil
+}
+
+func newMapKey(v reflect.Value) (mapKey, error) {
+	key := mapKey{value: v}
+	if v.Kind() == reflect.String {
+		key.name = v.String()
+		key.sort = key.name
+		return key, nil
+	}
+
+	// map keys aren't addressable, so copy the key for methods with
+	// pointer receivers
+	addressable := reflect.New(v.Type()).Elem()
+	addressable.Set(v)
+
+	if m, ok := implementation(addressable, textMarshalerType); ok {
+		b, err := m.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return key, fmt.Errorf("unable to convert map key %v: %w", v, err)
+		}
+		key.name = string(b)
+		key.sort = key.name
+		return key, nil
+	}
+	if s, ok := implementation(addressable, stringerType); ok {
+		key.name = s.Interface().(fmt.Stringer).String()
+		key.sort = key.name
+		return key, nil
+	}
+
+	var n uint64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		key.name = strconv.FormatInt(v.Int(), 10)
+		// flip the sign bit so negative numbers come first
+		n = uint64(v.Int()) ^ 1<<63
+	default:
+		key.name = strconv.FormatUint(v.Uint(), 10)
+		n = v.Uint()
+	}
+	key.sort = fmt.Sprintf("%020d", n)
+	return key, nil
+}
+
+// parseMapKey returns the key of type t that's named s
+func parseMapKey(s string, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(s).Convert(t), nil
+	}
+
+	key := reflect.New(t).Elem()
+	if implements(t, textUnmarshalerType) {
+		return key, setText(key, s)
+	}
+	return parseElem(s, t)
+}
+
+// mapKeyPath returns the path to the value of key in the map at path. The
+// path of an integer key is recorded with the key's sort name in its place,
+// so the columns under it sort numerically.
+func (c *converter) mapKeyPath(key mapKey, tags fieldTags, path []string) []string {
+	fieldPath := c.fieldPath(key.name, tags, path)
+	if key.sort != key.name {
+		c.sortPaths[strings.Join(fieldPath, "\x00")] = c.fieldPath(key.sort, tags, c.sortPath(path))
+	}
+	return fieldPath
+}
+
+// sortPath returns path with the names of integer map keys replaced by their
+// sort names
+func (c *converter) sortPath(path []string) []string {
+	for i := len(path); i > 0; i-- {
+		if sorted, ok := c.sortPaths[strings.Join(path[:i], "\x00")]; ok {
+			return append(sorted[:len(sorted):len(sorted)], path[i:]...)
+		}
+	}
+	return path
+}
+
+// cellName returns the name of the column of the value at path, and
+// records the name the column is sorted by when it's under an integer map
+// key
+func (c *converter) cellName(path []string) string {
+	if len(path) == 0 {
+		// values at the top level, like the result of a top level
+		// FrameValuer, are named after the frame
+		path = []string{c.scalarName}
+	}
+
+	name := c.columnName(path)
+	if len(c.sortPaths) > 0 {
+		if _, ok := c.sortKeys[name]; !ok {
+			c.sortKeys[name] = c.columnName(c.sortPath(path))
+		}
+	}
+	return name
+}
+
+// sortKey returns the name that the column called name is sorted by
+func (c *converter) sortKey(name string) string {
+	if key, ok := c.sortKeys[name]; ok {
+		return key
+	}
+	return name
+}
@@ -0,0 +1,79 @@
+package framestruct
+
+import "github.com/grafana/grafana-plugin-sdk-go/data"
+
+// FrameMetaProvider is implemented by types that set the metadata of the
+// frames they're converted into. When the value passed to ToDataFrame
+// implements it, the frame's Meta is a copy of the result of FrameMeta.
+// Metadata options are applied on top of it.
+type FrameMetaProvider interface {
+	FrameMeta() *data.FrameMeta
+}
+
+// WithFrameMeta sets the metadata of the frame. Metadata options that come
+// after it change the fields they set.
+func WithFrameMeta(meta *data.FrameMeta) Option {
+	return withMeta(func(m *data.FrameMeta) {
+		if meta != nil {
+			*m = *meta
+		}
+	})
+}
+
+// WithExecutedQueryString sets the query shown in Grafana's query inspector
+func WithExecutedQueryString(query string) Option {
+	return withMeta(func(m *data.FrameMeta) {
+		m.ExecutedQueryString = query
+	})
+}
+
+// WithNotices adds notices that Grafana displays with the frame
+func WithNotices(notices ...data.Notice) Option {
+	return withMeta(func(m *data.FrameMeta) {
+		// don't append to the notices of a provider's metadata
+		m.Notices = append(m.Notices[:len(m.Notices):len(m.Notices)], notices...)
+	})
+}
+
+// WithPreferredVisualization sets how Grafana's Explore shows the frame
+func WithPreferredVisualization(vis data.VisType) Option {
+	return withMeta(func(m *data.FrameMeta) {
+		m.PreferredVisualization = vis
+	})
+}
+
+// WithCustomMeta sets the datasource specific metadata of the frame
+func WithCustomMeta(custom interface{}) Option {
+	return withMeta(func(m *data.FrameMeta) {
+		m.Custom = custom
+	})
+}
+
+func withMeta(fn func(*data.FrameMeta)) Option {
+	return func(c *converter) {
+		c.metaOpts = append(c.metaOpts, fn)
+	}
+}
+
+// setMetaProvider uses the metadata of v if it's a FrameMetaProvider
+func (c *converter) setMetaProvider(v interface{}) {
+	if p, ok := v.(FrameMetaProvider); ok {
+		c.meta = p.FrameMeta()
+	}
+}
+
+// frameMeta returns the metadata of the frame, or nil if there isn't any
+func (c *converter) frameMeta() *data.FrameMeta {
+	if c.meta == nil && len(c.metaOpts) == 0 {
+		return nil
+	}
+
+	meta := &data.FrameMeta{}
+	if c.meta != nil {
+		*meta = *c.meta
+	}
+	for _, opt := range c.metaOpts {
+		opt(meta)
+	}
+	return meta
+}
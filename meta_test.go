@@ -0,0 +1,99 @@
+package framestruct_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameMeta(t *testing.T) {
+	t.Run("it doesn't set metadata by default", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", simpleStruct{"foo", 36, "baz"})
+		require.Nil(t, err)
+		require.Nil(t, frame.Meta)
+	})
+
+	t.Run("it sets metadata from options", func(t *testing.T) {
+		notice := data.Notice{Severity: data.NoticeSeverityWarning, Text: "results were truncated"}
+
+		frame, err := framestruct.ToDataFrame("results", simpleStruct{"foo", 36, "baz"},
+			framestruct.WithExecutedQueryString("SELECT * FROM things"),
+			framestruct.WithNotices(notice),
+			framestruct.WithPreferredVisualization(data.VisTypeTable),
+			framestruct.WithCustomMeta(map[string]int{"scanned": 100}),
+		)
+		require.Nil(t, err)
+
+		require.Equal(t, &data.FrameMeta{
+			ExecutedQueryString:    "SELECT * FROM things",
+			Notices:                []data.Notice{notice},
+			PreferredVisualization: data.VisTypeTable,
+			Custom:                 map[string]int{"scanned": 100},
+		}, frame.Meta)
+	})
+
+	t.Run("it sets metadata from a FrameMetaProvider", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", metaProvider{"foo"})
+		require.Nil(t, err)
+
+		require.Equal(t, "SELECT foo", frame.Meta.ExecutedQueryString)
+		require.Equal(t, []string{"Query"}, fieldNames(frame))
+	})
+
+	t.Run("it applies options on top of a FrameMetaProvider", func(t *testing.T) {
+		notice := data.Notice{Text: "cached"}
+
+		frame, err := framestruct.ToDataFrame("results", metaProvider{"foo"},
+			framestruct.WithNotices(notice),
+			framestruct.WithPreferredVisualization(data.VisTypeGraph),
+		)
+		require.Nil(t, err)
+
+		require.Equal(t, "SELECT foo", frame.Meta.ExecutedQueryString)
+		require.Equal(t, []data.Notice{{Text: "from provider"}, notice}, frame.Meta.Notices)
+		require.Equal(t, data.VisTypeGraph, frame.Meta.PreferredVisualization)
+	})
+
+	t.Run("it replaces earlier metadata with WithFrameMeta", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", metaProvider{"foo"},
+			framestruct.WithFrameMeta(&data.FrameMeta{Path: "/things"}),
+			framestruct.WithExecutedQueryString("SELECT bar"),
+		)
+		require.Nil(t, err)
+
+		require.Equal(t, &data.FrameMeta{Path: "/things", ExecutedQueryString: "SELECT bar"}, frame.Meta)
+	})
+
+	t.Run("it keeps metadata when building time series", func(t *testing.T) {
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+		rows := []measurement{
+			{tme, "a", 1, 0.5, ""},
+			{tme, "b", 1, 0.75, ""},
+		}
+
+		frame, err := framestruct.ToWideFrame("results", rows, framestruct.WithExecutedQueryString("SELECT cpu"))
+		require.Nil(t, err)
+		require.Equal(t, "SELECT cpu", frame.Meta.ExecutedQueryString)
+
+		frames, err := framestruct.ToSeriesFrames("results", rows, framestruct.WithExecutedQueryString("SELECT cpu"))
+		require.Nil(t, err)
+		require.Len(t, frames, 2)
+		for _, f := range frames {
+			require.Equal(t, "SELECT cpu", f.Meta.ExecutedQueryString)
+		}
+	})
+}
+
+type metaProvider struct {
+	Query string
+}
+
+func (p metaProvider) FrameMeta() *data.FrameMeta {
+	return &data.FrameMeta{
+		ExecutedQueryString: "SELECT " + p.Query,
+		Notices:             []data.Notice{{Text: "from provider"}},
+	}
+}
@@ -0,0 +1,109 @@
+package framestruct
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FieldNamer builds a field name from the names of a value and its parents,
+// outermost first. Tags and omitparent have already been applied to path.
+type FieldNamer func(path []string) string
+
+// WithFieldNamer sets how field names are built from the names of a value and
+// its parents. By default, they are joined with the separator. When a
+// FieldNamer is set, the separator is ignored.
+func WithFieldNamer(namer FieldNamer) Option {
+	return func(c *converter) {
+		c.namer = namer
+	}
+}
+
+// WithNameTransform sets a function that transforms the names of struct
+// fields, e.g. SnakeCase. Names from struct tags and map keys are used as-is.
+func WithNameTransform(transform func(string) string) Option {
+	return func(c *converter) {
+		c.nameTransform = transform
+	}
+}
+
+func (c *converter) columnName(path []string) string {
+	if c.namer != nil {
+		return c.namer(path)
+	}
+
+	if len(path) == 1 {
+		return path[0]
+	}
+	return strings.Join(path, c.separator)
+}
+
+func (c *converter) structFieldName(name string) string {
+	if c.nameTransform != nil {
+		return c.nameTransform(name)
+	}
+	return name
+}
+
+// SnakeCase converts a Go name to snake_case, e.g. HTTPStatusCode becomes
+// http_status_code
+func SnakeCase(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		if b.Len() > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteString(strings.ToLower(word))
+	}
+	return b.String()
+}
+
+// CamelCase converts a Go name to camelCase, e.g. HTTPStatusCode becomes
+// httpStatusCode
+func CamelCase(name string) string {
+	var b strings.Builder
+	for i, word := range words(name) {
+		if i == 0 {
+			b.WriteString(strings.ToLower(word))
+			continue
+		}
+
+		r := []rune(strings.ToLower(word))
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// words splits a Go name into words at underscores and changes of case.
+// Runs of capitals are treated as a single word, so HTTPStatus is split into
+// HTTP and Status.
+func words(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '_' {
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+
+		prev := runes[i-1]
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if !unicode.IsUpper(prev) || nextIsLower {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,101 @@
+package framestruct_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNaming(t *testing.T) {
+	t.Run("it transforms struct field names", func(t *testing.T) {
+		strct := casedStruct{
+			HTTPStatusCode: 200,
+			UserID:         "foo",
+			Nested:         casedNested{FirstName: "bar", Tagged: "baz"},
+			Map:            map[string]interface{}{"SomeKey": "qux"},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct,
+			framestruct.WithNameTransform(framestruct.SnakeCase),
+			framestruct.WithMapKeyOrder(framestruct.SortMapKeys),
+		)
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"http_status_code",
+			"user_id",
+			"nested.first_name",
+			"nested.TaggedName",
+			"map.SomeKey",
+		}, fieldNames(frame))
+
+		var out casedStruct
+		err = framestruct.FromDataFrame(frame, &out, framestruct.WithNameTransform(framestruct.SnakeCase))
+		require.Nil(t, err)
+		require.Equal(t, strct, out)
+	})
+
+	t.Run("it builds names with the field namer", func(t *testing.T) {
+		namer := func(path []string) string {
+			return strings.ToUpper(strings.Join(path, "/"))
+		}
+
+		strct := nested1{"foo", 36, "baz", nested3{true, 100}}
+
+		frame, err := framestruct.ToDataFrame("results", strct, framestruct.WithFieldNamer(namer))
+		require.Nil(t, err)
+		require.Equal(t, []string{"THING1", "THING2", "THING3", "THING4/THING7", "THING4/THING8"}, fieldNames(frame))
+
+		var out nested1
+		err = framestruct.FromDataFrame(frame, &out, framestruct.WithFieldNamer(namer))
+		require.Nil(t, err)
+		require.Equal(t, strct, out)
+	})
+
+	t.Run("it passes paths with omitted parents to the field namer", func(t *testing.T) {
+		var paths [][]string
+		namer := func(path []string) string {
+			paths = append(paths, path)
+			return strings.Join(path, "/")
+		}
+
+		strct := omitParentStruct{"foo", "bar", nested2{true, 100}, nested3{false, 200}}
+
+		_, err := framestruct.ToDataFrame("results", strct, framestruct.WithFieldNamer(namer))
+		require.Nil(t, err)
+		require.Contains(t, paths, []string{"Thing5"})
+		require.Contains(t, paths, []string{"omitparent", "Thing7"})
+	})
+}
+
+func TestCase(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		snake string
+		camel string
+	}{
+		{"Thing", "thing", "thing"},
+		{"Thing1", "thing1", "thing1"},
+		{"FirstName", "first_name", "firstName"},
+		{"HTTPStatusCode", "http_status_code", "httpStatusCode"},
+		{"UserID", "user_id", "userId"},
+		{"ID", "id", "id"},
+		{"already_snake", "already_snake", "alreadySnake"},
+	} {
+		require.Equal(t, tc.snake, framestruct.SnakeCase(tc.name))
+		require.Equal(t, tc.camel, framestruct.CamelCase(tc.name))
+	}
+}
+
+type casedStruct struct {
+	HTTPStatusCode int64
+	UserID         string
+	Nested         casedNested
+	Map            map[string]interface{}
+}
+
+type casedNested struct {
+	FirstName string
+	Tagged    string `frame:"TaggedName"`
+}
@@ -0,0 +1,109 @@
+package framestruct
+
+import (
+	"errors"
+	"time"
+)
+
+// Option configures how values are converted to and from data.Frames
+type Option func(*converter)
+
+// ErrMaxRows is returned when a frame would have more rows than WithMaxRows
+// allows
+var ErrMaxRows = errors.New("frame has reached the maximum number of rows")
+
+// MapKeyOrder controls how the fields produced by maps are ordered
+type MapKeyOrder int
+
+const (
+	// SortAllFields sorts every field name alphabetically when any map is
+	// converted. This is the default.
+	SortAllFields MapKeyOrder = iota
+
+	// SortMapKeys keeps fields in the order they are first seen and visits
+	// map keys in sorted order, so struct fields keep their declaration order
+	SortMapKeys
+)
+
+// WithSeparator sets the separator between the names of parents and
+// children. The default is ".", e.g. parent.child
+func WithSeparator(sep string) Option {
+	return func(c *converter) {
+		c.separator = sep
+	}
+}
+
+// WithTagKey sets the key of the struct tag that configures conversion.
+// The default is "frame"
+func WithTagKey(key string) Option {
+	return func(c *converter) {
+		c.tagKey = key
+	}
+}
+
+// WithMapKeyOrder sets how fields that come from maps are ordered
+func WithMapKeyOrder(order MapKeyOrder) Option {
+	return func(c *converter) {
+		c.mapKeyOrder = order
+	}
+}
+
+// WithColumnOrder puts the columns called names first, in that order.
+// Names that aren't columns of the frame are ignored. It takes precedence
+// over the col0 and order tag options.
+func WithColumnOrder(names []string) Option {
+	return func(c *converter) {
+		c.columnOrder = names
+	}
+}
+
+// WithColumnName sets the name of the column of frames converted from
+// slices of values, like []float64 or []time.Time. The default is the name
+// of the frame.
+func WithColumnName(name string) Option {
+	return func(c *converter) {
+		c.scalarName = name
+	}
+}
+
+// WithNullRows converts nil elements of slices like []*T into rows where
+// every value is null, rather than skipping them. FrameBuilders append a
+// null row for a nil row instead of returning an error.
+func WithNullRows() Option {
+	return func(c *converter) {
+		c.nullRows = true
+	}
+}
+
+// WithSkipUnsupported skips values that can't be converted rather than
+// returning an error
+func WithSkipUnsupported() Option {
+	return func(c *converter) {
+		c.skipUnsupported = true
+	}
+}
+
+// WithStringFallback converts values that implement encoding.TextMarshaler or
+// fmt.Stringer to strings when their type couldn't be converted otherwise.
+// Use the string tag option to convert a single field to a string.
+func WithStringFallback() Option {
+	return func(c *converter) {
+		c.stringFallback = true
+	}
+}
+
+// WithTimeLocation converts every time to loc
+func WithTimeLocation(loc *time.Location) Option {
+	return func(c *converter) {
+		c.location = loc
+	}
+}
+
+// WithMaxRows limits frames to n rows. Converting more rows returns
+// ErrMaxRows. FrameBuilders, ToDataFrameFromChan, and ToDataFrameFromIter
+// keep the rows converted before the limit was reached.
+func WithMaxRows(n int) Option {
+	return func(c *converter) {
+		c.maxRows = n
+	}
+}
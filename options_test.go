@@ -0,0 +1,161 @@
+package framestruct_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	t.Run("it joins nested names with the separator", func(t *testing.T) {
+		strct := nested1{"foo", 36, "baz", nested3{true, 100}}
+
+		frame, err := framestruct.ToDataFrame("results", strct, framestruct.WithSeparator("_"))
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 5)
+		require.Equal(t, "Thing4_Thing7", frame.Fields[3].Name)
+		require.Equal(t, "Thing4_Thing8", frame.Fields[4].Name)
+
+		var out nested1
+		err = framestruct.FromDataFrame(frame, &out, framestruct.WithSeparator("_"))
+		require.Nil(t, err)
+		require.Equal(t, strct, out)
+	})
+
+	t.Run("it reads tags from the tag key", func(t *testing.T) {
+		strct := customTagKey{"foo", "bar"}
+
+		frame, err := framestruct.ToDataFrame("results", strct, framestruct.WithTagKey("json"))
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, "first", frame.Fields[0].Name)
+		require.Equal(t, "foo", frame.Fields[0].At(0))
+
+		var out customTagKey
+		err = framestruct.FromDataFrame(frame, &out, framestruct.WithTagKey("json"))
+		require.Nil(t, err)
+		require.Equal(t, customTagKey{Thing1: "foo"}, out)
+	})
+
+	t.Run("it keeps struct fields in declaration order when sorting map keys", func(t *testing.T) {
+		strct := structWithMapAndFields{
+			Zed: "zed",
+			Foo: map[string]interface{}{
+				"ccc": "foo",
+				"aaa": "foo",
+				"bbb": "foo",
+			},
+			Alpha: "alpha",
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+		require.Equal(t, []string{"Alpha", "Foo.aaa", "Foo.bbb", "Foo.ccc", "Zed"}, fieldNames(frame))
+
+		frame, err = framestruct.ToDataFrame("results", strct, framestruct.WithMapKeyOrder(framestruct.SortMapKeys))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Zed", "Foo.aaa", "Foo.bbb", "Foo.ccc", "Alpha"}, fieldNames(frame))
+	})
+
+	t.Run("it puts columns in the column order first", func(t *testing.T) {
+		strct := structWithMapAndFields{
+			Zed: "zed",
+			Foo: map[string]interface{}{
+				"ccc": "foo",
+				"aaa": "foo",
+				"bbb": "foo",
+			},
+			Alpha: "alpha",
+		}
+		order := framestruct.WithColumnOrder([]string{"Foo.bbb", "Zed", "missing"})
+
+		frame, err := framestruct.ToDataFrame("results", strct, order)
+		require.Nil(t, err)
+		require.Equal(t, []string{"Foo.bbb", "Zed", "Alpha", "Foo.aaa", "Foo.ccc"}, fieldNames(frame))
+
+		frame, err = framestruct.ToDataFrame("results", strct, order, framestruct.WithMapKeyOrder(framestruct.SortMapKeys))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Foo.bbb", "Zed", "Foo.aaa", "Foo.ccc", "Alpha"}, fieldNames(frame))
+
+		frame, err = framestruct.ToDataFrame("results", orderedTags{Zed: "zed", Alpha: "alpha"}, framestruct.WithColumnOrder([]string{"Beta"}))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Beta", "Alpha", "Zed"}, fieldNames(frame))
+	})
+
+	t.Run("it skips unsupported values", func(t *testing.T) {
+		strct := supportedWithUnsupported{"foo", unsupportedType{32}}
+
+		frame, err := framestruct.ToDataFrame("results", strct, framestruct.WithSkipUnsupported())
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, "Foo", frame.Fields[0].Name)
+
+		frame, err = framestruct.ToDataFrame("results", unsupportedTypeSlice{[]string{"1"}}, framestruct.WithSkipUnsupported())
+		require.Nil(t, err)
+		require.Len(t, frame.Fields, 0)
+	})
+
+	t.Run("it converts times to the time location", func(t *testing.T) {
+		loc := time.FixedZone("UTC+1", 60*60)
+		tme := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+
+		frame, err := framestruct.ToDataFrame("results", timeStruct{tme}, framestruct.WithTimeLocation(loc))
+		require.Nil(t, err)
+		require.Equal(t, loc, frame.Fields[0].At(0).(time.Time).Location())
+		require.True(t, tme.Equal(frame.Fields[0].At(0).(time.Time)))
+
+		frame, err = framestruct.ToDataFrame("results", timePointerStruct{&tme}, framestruct.WithTimeLocation(loc))
+		require.Nil(t, err)
+		require.Equal(t, loc, frame.Fields[0].At(0).(*time.Time).Location())
+		require.Equal(t, time.UTC, tme.Location())
+	})
+
+	t.Run("it limits the number of rows", func(t *testing.T) {
+		strcts := []simpleStruct{
+			{"foo", 36, "baz"},
+			{"foo1", 37, "baz1"},
+		}
+
+		_, err := framestruct.ToDataFrame("results", strcts, framestruct.WithMaxRows(1))
+		require.True(t, errors.Is(err, framestruct.ErrMaxRows))
+
+		_, err = framestruct.ToDataFrame("results", explodedSlice{"foo", []string{"a", "b"}}, framestruct.WithMaxRows(1))
+		require.True(t, errors.Is(err, framestruct.ErrMaxRows))
+
+		builder, err := framestruct.NewFrameBuilder("results", explodedSlice{}, framestruct.WithMaxRows(3))
+		require.Nil(t, err)
+		require.Nil(t, builder.Append(explodedSlice{"foo", []string{"a", "b"}}))
+		require.True(t, errors.Is(builder.Append(explodedSlice{"bar", []string{"a", "b"}}), framestruct.ErrMaxRows))
+		require.Equal(t, 2, builder.Rows())
+		require.Nil(t, builder.Append(explodedSlice{"baz", []string{"a"}}))
+		frame, err := builder.Frame()
+		require.Nil(t, err)
+		require.Equal(t, 3, frame.Fields[0].Len())
+	})
+}
+
+func fieldNames(frame *data.Frame) []string {
+	var names []string
+	for _, f := range frame.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+type customTagKey struct {
+	Thing1 string `json:"first"`
+	Thing2 string `json:"-"`
+}
+
+type structWithMapAndFields struct {
+	Zed   string
+	Foo   map[string]interface{}
+	Alpha string
+}
@@ -0,0 +1,182 @@
+package framestruct
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ToWideFrame converts rows of a long time series, like those accepted by
+// ToDataFrame, into a wide frame with a value field for every series, as
+// Grafana's time series panels expect. See data.LongToWide for the details,
+// including that rows must be sorted by time.
+//
+// The time index is the col0 field if it's a time, otherwise the first time
+// field. Fields tagged label identify a series and fields tagged value hold
+// its values. Without those tags, strings and bools are labels and numbers
+// are values. Any other field is dropped.
+func ToWideFrame(name string, rows interface{}, opts ...Option) (*data.Frame, error) {
+	c := newConverter(opts...)
+	c.keepLabels = true
+	long, err := c.toLongFrame(name, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if long.TimeSeriesSchema().Type == data.TimeSeriesTypeWide {
+		// without labels there's only one series, so it's already wide
+		return long, nil
+	}
+	return data.LongToWide(long, c.fillMissing)
+}
+
+// ToSeriesFrames converts rows of a long time series into a frame for every
+// series. Each frame has the time field and the value fields of one series,
+// and its value fields are labelled with the series' labels. Time, label, and
+// value fields are chosen like ToWideFrame, but rows don't need to be sorted.
+// Frames are in the order their series first appear.
+func ToSeriesFrames(name string, rows interface{}, opts ...Option) (data.Frames, error) {
+	c := newConverter(opts...)
+	c.keepLabels = true
+	long, err := c.toLongFrame(name, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return seriesFrames(long), nil
+}
+
+// WithFillMissing sets how ToWideFrame fills in the values of series that
+// don't have a row at every time
+func WithFillMissing(fillMissing *data.FillMissing) Option {
+	return func(c *converter) {
+		c.fillMissing = fillMissing
+	}
+}
+
+// toLongFrame converts v into a frame that only has its time, label, and
+// value fields. Labels that aren't strings or bools are converted to
+// strings.
+func (c *converter) toLongFrame(name string, v interface{}) (*data.Frame, error) {
+	frame, err := c.toDataframe(name, v)
+	if err != nil {
+		return nil, err
+	}
+
+	timeIndex := -1
+	for i, f := range frame.Fields {
+		if f.Type().Time() {
+			timeIndex = i
+			break
+		}
+	}
+	if timeIndex < 0 {
+		return nil, errors.New("time series must have a time field")
+	}
+
+	long := data.NewFrame(name, frame.Fields[timeIndex])
+	long.Meta = frame.Meta
+	for i, f := range frame.Fields {
+		switch {
+		case i == timeIndex:
+			continue
+		case c.labelFields[f.Name]:
+			long.Fields = append(long.Fields, labelField(f))
+		case c.valueFields[f.Name]:
+			if !f.Type().Numeric() {
+				return nil, fmt.Errorf("value field %s must be numeric", f.Name)
+			}
+			long.Fields = append(long.Fields, f)
+		case len(c.labelFields) == 0 && isFactor(f.Type()):
+			long.Fields = append(long.Fields, f)
+		case len(c.valueFields) == 0 && f.Type().Numeric():
+			long.Fields = append(long.Fields, f)
+		}
+	}
+
+	if len(long.TimeSeriesSchema().ValueIndices) == 0 {
+		return nil, errors.New("time series must have a value field")
+	}
+	return long, nil
+}
+
+// isFactor reports whether data.LongToWide treats fields of type ft as labels
+func isFactor(ft data.FieldType) bool {
+	switch ft {
+	case data.FieldTypeString, data.FieldTypeNullableString, data.FieldTypeBool, data.FieldTypeNullableBool:
+		return true
+	}
+	return false
+}
+
+// labelField returns f, or a copy of f that stores its values as strings
+func labelField(f *data.Field) *data.Field {
+	if isFactor(f.Type()) {
+		return f
+	}
+
+	values := make([]*string, f.Len())
+	for i := range values {
+		if v, ok := f.ConcreteAt(i); ok {
+			s := fmt.Sprint(v)
+			values[i] = &s
+		}
+	}
+	return data.NewField(f.Name, f.Labels, values)
+}
+
+// seriesFrames splits a long frame into a frame for every combination of its
+// labels
+func seriesFrames(long *data.Frame) data.Frames {
+	schema := long.TimeSeriesSchema()
+
+	var frames data.Frames
+	series := make(map[string]*data.Frame)
+	for row := 0; row < long.Rows(); row++ {
+		labels := make(data.Labels, len(schema.FactorIndices))
+		for _, i := range schema.FactorIndices {
+			labels[long.Fields[i].Name] = labelValue(long.Fields[i], row)
+		}
+
+		key := labels.String()
+		frame, ok := series[key]
+		if !ok {
+			frame = newSeriesFrame(long, schema, labels)
+			series[key] = frame
+			frames = append(frames, frame)
+		}
+
+		frame.Fields[0].Append(long.Fields[schema.TimeIndex].At(row))
+		for i, v := range schema.ValueIndices {
+			frame.Fields[i+1].Append(long.Fields[v].At(row))
+		}
+	}
+	return frames
+}
+
+func newSeriesFrame(long *data.Frame, schema data.TimeSeriesSchema, labels data.Labels) *data.Frame {
+	timeField := long.Fields[schema.TimeIndex]
+	frame := data.NewFrame(long.Name, data.NewFieldFromFieldType(timeField.Type(), 0))
+	frame.Fields[0].Name = timeField.Name
+	frame.Meta = long.Meta
+
+	for _, i := range schema.ValueIndices {
+		f := data.NewFieldFromFieldType(long.Fields[i].Type(), 0)
+		f.Name = long.Fields[i].Name
+		f.Labels = labels.Copy()
+		f.Config = long.Fields[i].Config
+		frame.Fields = append(frame.Fields, f)
+	}
+	return frame
+}
+
+// labelValue returns the value of a string or bool field as a label. Nulls
+// are empty strings, like data.LongToWide.
+func labelValue(f *data.Field, row int) string {
+	v, ok := f.ConcreteAt(row)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,138 @@
+package framestruct_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToWideFrame(t *testing.T) {
+	t1 := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+
+	t.Run("it makes a value field for every series", func(t *testing.T) {
+		rows := []measurement{
+			{t1, "a", 1, 0.5, "dropped"},
+			{t1, "b", 1, 0.25, "dropped"},
+			{t2, "a", 1, 0.75, "dropped"},
+			{t2, "b", 1, 0.5, "dropped"},
+		}
+
+		frame, err := framestruct.ToWideFrame("results", rows)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"time", "cpu", "cpu"}, fieldNames(frame))
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Equal(t, t2, frame.Fields[0].At(1))
+
+		require.Equal(t, data.Labels{"host": "a", "region": "1"}, frame.Fields[1].Labels)
+		require.Equal(t, 0.5, frame.Fields[1].At(0))
+		require.Equal(t, 0.75, frame.Fields[1].At(1))
+
+		require.Equal(t, data.Labels{"host": "b", "region": "1"}, frame.Fields[2].Labels)
+		require.Equal(t, 0.25, frame.Fields[2].At(0))
+		require.Equal(t, 0.5, frame.Fields[2].At(1))
+	})
+
+	t.Run("it uses strings as labels and numbers as values without tags", func(t *testing.T) {
+		rows := []untaggedMeasurement{
+			{t1, "a", 1},
+			{t1, "b", 2},
+		}
+
+		frame, err := framestruct.ToWideFrame("results", rows)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Time", "Value", "Value"}, fieldNames(frame))
+		require.Equal(t, data.Labels{"Host": "a"}, frame.Fields[1].Labels)
+		require.Equal(t, data.Labels{"Host": "b"}, frame.Fields[2].Labels)
+	})
+
+	t.Run("it fills in missing values", func(t *testing.T) {
+		rows := []untaggedMeasurement{
+			{t1, "a", 1},
+			{t1, "b", 2},
+			{t2, "a", 3},
+		}
+
+		frame, err := framestruct.ToWideFrame("results", rows, framestruct.WithFillMissing(&data.FillMissing{
+			Mode:  data.FillModeValue,
+			Value: -1,
+		}))
+		require.Nil(t, err)
+		require.Equal(t, float64(-1), frame.Fields[2].At(1))
+	})
+
+	t.Run("it returns a single series as it is", func(t *testing.T) {
+		rows := []measurement{
+			{t1, "a", 1, 0.5, "dropped"},
+			{t2, "a", 1, 0.75, "dropped"},
+		}
+
+		frame, err := framestruct.ToWideFrame("results", rows, framestruct.WithTagKey("series"))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Time", "CPU"}, fieldNames(frame))
+	})
+
+	t.Run("it returns an error without a time field", func(t *testing.T) {
+		_, err := framestruct.ToWideFrame("results", []simpleStruct{{"foo", 36, "baz"}})
+		require.Error(t, err)
+		require.Equal(t, "time series must have a time field", err.Error())
+	})
+
+	t.Run("it returns an error when a value isn't a number", func(t *testing.T) {
+		_, err := framestruct.ToWideFrame("results", []stringValue{{t1, "foo"}})
+		require.Error(t, err)
+		require.Equal(t, "value field Value must be numeric", err.Error())
+	})
+}
+
+func TestToSeriesFrames(t *testing.T) {
+	t1 := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+
+	t.Run("it makes a frame for every series", func(t *testing.T) {
+		rows := []measurement{
+			{t1, "b", 1, 0.25, "dropped"},
+			{t1, "a", 1, 0.5, "dropped"},
+			{t2, "b", 1, 0.5, "dropped"},
+		}
+
+		frames, err := framestruct.ToSeriesFrames("results", rows)
+		require.Nil(t, err)
+		require.Len(t, frames, 2)
+
+		require.Equal(t, "results", frames[0].Name)
+		require.Equal(t, []string{"time", "cpu"}, fieldNames(frames[0]))
+		require.Equal(t, data.Labels{"host": "b", "region": "1"}, frames[0].Fields[1].Labels)
+		require.Equal(t, 2, frames[0].Fields[0].Len())
+		require.Equal(t, t2, frames[0].Fields[0].At(1))
+		require.Equal(t, 0.5, frames[0].Fields[1].At(1))
+
+		require.Equal(t, data.Labels{"host": "a", "region": "1"}, frames[1].Fields[1].Labels)
+		require.Equal(t, 1, frames[1].Fields[0].Len())
+		require.Equal(t, 0.5, frames[1].Fields[1].At(0))
+	})
+}
+
+type measurement struct {
+	Time   time.Time `frame:"time"`
+	Host   string    `frame:"host,label" series:"-"`
+	Region int32     `frame:"region,label" series:"-"`
+	CPU    float64   `frame:"cpu,value"`
+	Note   string    `series:"-"`
+}
+
+type untaggedMeasurement struct {
+	Time  time.Time
+	Host  string
+	Value float64
+}
+
+type stringValue struct {
+	Time  time.Time
+	Value string `frame:",value"`
+}
@@ -0,0 +1,217 @@
+package framestruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structInfo is the reflection metadata of a struct type that doesn't depend
+// on a converter's options. It's computed once per type and tag key.
+type structInfo struct {
+	fields []fieldInfo
+	// err is set when a field's tags are invalid
+	err error
+}
+
+type fieldInfo struct {
+	index int
+	name  string
+	tags  fieldTags
+	typ   reflect.Type
+
+	// leaf is set when values of typ, or the values typ points to, are
+	// stored directly in a field
+	leaf bool
+	// valuer is set when typ implements FrameValuer
+	valuer bool
+}
+
+type structInfoKey struct {
+	typ    reflect.Type
+	tagKey string
+}
+
+var structInfos sync.Map // map[structInfoKey]*structInfo
+
+func cachedStructInfo(t reflect.Type, tagKey string) *structInfo {
+	key := structInfoKey{t, tagKey}
+	if info, ok := structInfos.Load(key); ok {
+		return info.(*structInfo)
+	}
+
+	info, _ := structInfos.LoadOrStore(key, newStructInfo(t, tagKey))
+	return info.(*structInfo)
+}
+
+func newStructInfo(t reflect.Type, tagKey string) *structInfo {
+	info := &structInfo{}
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		tag := structField.Tag.Get(tagKey)
+		if tag == "-" {
+			continue
+		}
+
+		tags, err := parseTags(tag)
+		if err == nil {
+			_, err = tags.fieldConfig()
+		}
+		if err != nil && info.err == nil {
+			info.err = fmt.Errorf("invalid %s tag on %s: %w", tagKey, structField.Name, err)
+		}
+
+		info.fields = append(info.fields, fieldInfo{
+			index:  i,
+			name:   structField.Name,
+			tags:   tags,
+			typ:    structField.Type,
+			leaf:   fieldType(derefType(structField.Type)) != nil,
+			valuer: implements(structField.Type, valuerType),
+		})
+	}
+	return info
+}
+
+// structPlan is a struct type compiled for one converter: the names of its
+// columns are resolved and every field knows how it's converted, so
+// converting a row only has to extract values.
+type structPlan struct {
+	fields []fieldPlan
+}
+
+type fieldPlan struct {
+	*fieldInfo
+	kind  planKind
+	path  []string
+	name  string
+	child *structPlan
+}
+
+type planKind int
+
+const (
+	// planValue fields are converted by handleValue
+	planValue planKind = iota
+	// planLeaf fields are stored as they are
+	planLeaf
+	// planStruct fields are nested structs with their own plan
+	planStruct
+	// planLabel fields are labels of the frame's value fields
+	planLabel
+	// planLabels fields are maps of labels
+	planLabels
+)
+
+type planKey struct {
+	typ  reflect.Type
+	path string
+}
+
+// plan returns the plan for struct type t at path, compiling it on first use
+func (c *converter) plan(t reflect.Type, path []string) (*structPlan, error) {
+	key := planKey{t, strings.Join(path, "\x00")}
+	if p, ok := c.plans[key]; ok {
+		return p, nil
+	}
+
+	p, err := c.compilePlan(t, path)
+	if err != nil {
+		return nil, err
+	}
+	c.plans[key] = p
+	return p, nil
+}
+
+func (c *converter) compilePlan(t reflect.Type, path []string) (*structPlan, error) {
+	info := cachedStructInfo(t, c.tagKey)
+	if info.err != nil {
+		return nil, info.err
+	}
+
+	p := &structPlan{fields: make([]fieldPlan, len(info.fields))}
+
+	for i := range info.fields {
+		fi := &info.fields[i]
+		fp := &p.fields[i]
+
+		fp.fieldInfo = fi
+		fp.path = c.fieldPath(c.structFieldName(fi.name), fi.tags, path)
+		fp.name = c.columnName(fp.path)
+
+		if fi.tags.label {
+			c.labelFields[fp.name] = true
+		}
+		if fi.tags.value {
+			c.valueFields[fp.name] = true
+		}
+		if fi.tags.hasConfig() {
+			c.configs[fp.name] = fi.tags
+		}
+		if fi.tags.ordered {
+			c.orders[fp.name] = fi.tags.order
+		}
+
+		switch {
+		case fi.tags.label && !c.keepLabels && fi.typ.Kind() == reflect.Map:
+			fp.kind = planLabels
+		case fi.tags.label && !c.keepLabels:
+			fp.kind = planLabel
+		case fi.valuer, c.storesText(fi.typ, fi.tags.asString):
+			fp.kind = planValue
+		case fi.leaf:
+			fp.kind = planLeaf
+		case isNestedStruct(fi.typ):
+			fp.kind = planStruct
+
+			var err error
+			if fp.child, err = c.plan(fi.typ, fp.path); err != nil {
+				return nil, err
+			}
+		default:
+			fp.kind = planValue
+		}
+	}
+	return p, nil
+}
+
+// convertPlan converts the fields of struct v using its plan
+func (c *converter) convertPlan(v reflect.Value, p *structPlan) error {
+	for i := range p.fields {
+		fp := &p.fields[i]
+		field := v.Field(fp.index)
+
+		var err error
+		switch fp.kind {
+		case planLeaf:
+			name := fp.name
+			if len(c.sortPaths) > 0 {
+				// records the name the column is sorted by under integer
+				// map keys
+				name = c.cellName(fp.path)
+			}
+			c.row.cells = append(c.row.cells, cell{name, field})
+		case planStruct:
+			err = c.convertPlan(field, fp.child)
+		case planLabel:
+			err = c.setLabel(fp.name, field)
+		case planLabels:
+			err = c.setLabels(field)
+		default:
+			err = c.handleValue(field, fp.tags, fp.path)
+		}
+		if err != nil {
+			return err
+		}
+
+		if fp.tags.col0 {
+			c.col0 = fp.name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package framestruct
+
+import "reflect"
+
+// cell is a converted value waiting to be written to the named field
+type cell struct {
+	name  string
+	value reflect.Value
+}
+
+// row holds the cells converted from a single top level value. Slices that
+// produce a row per element are kept as groups of child rows until the row
+// is written.
+type row struct {
+	cells  []cell
+	groups []group
+}
+
+// group is the child rows of a slice. at is the number of the parent's
+// cells that came before the slice, so its cells keep their position.
+type group struct {
+	at   int
+	rows []*row
+}
+
+func (r *row) reset() {
+	r.cells = r.cells[:0]
+	r.groups = r.groups[:0]
+}
+
+// addGroup adds the child rows of a slice after the cells added so far
+func (r *row) addGroup(rows []*row) {
+	r.groups = append(r.groups, group{at: len(r.cells), rows: rows})
+}
+
+// expand returns the cells of every row described by r: the cartesian
+// product of r's cells with one child row from each of its groups. Empty
+// groups are skipped so the rest of the row is still written.
+func (r *row) expand() [][]cell {
+	rows := [][]cell{nil}
+	prev := 0
+	for _, g := range r.groups {
+		rows = appendCells(rows, r.cells[prev:g.at])
+		prev = g.at
+		if len(g.rows) == 0 {
+			continue
+		}
+
+		var children [][]cell
+		for _, child := range g.rows {
+			children = append(children, child.expand()...)
+		}
+
+		expanded := make([][]cell, 0, len(rows)*len(children))
+		for _, parent := range rows {
+			for _, child := range children {
+				cells := make([]cell, 0, len(parent)+len(child))
+				cells = append(cells, parent...)
+				expanded = append(expanded, append(cells, child...))
+			}
+		}
+		rows = expanded
+	}
+	return appendCells(rows, r.cells[prev:])
+}
+
+// appendCells appends cells to every row
+func appendCells(rows [][]cell, cells []cell) [][]cell {
+	if len(cells) == 0 {
+		return rows
+	}
+	for i := range rows {
+		rows[i] = append(rows[i], cells...)
+	}
+	return rows
+}
@@ -0,0 +1,230 @@
+package framestruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sliceExplode = "explode"
+	sliceJoin    = "join"
+	sliceIndex   = "index"
+
+	defaultDelim = ","
+)
+
+// isScalarSlice reports whether t is a slice of values that would each be a
+// single column, as opposed to a slice of structs or maps
+func isScalarSlice(t reflect.Type) bool {
+	e := t.Elem()
+	if implements(e, valuerType) {
+		// FrameValuers are converted into values
+		return true
+	}
+
+	switch e.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Interface:
+		return false
+	case reflect.Ptr, reflect.Struct:
+		// times and named times are values, other structs aren't
+		return isScalar(e)
+	default:
+		return true
+	}
+}
+
+// convertScalarSlice converts a slice of values according to the slice
+// option in the field's tags:
+//
+//	explode: each element becomes its own row alongside the rest of the row
+//	join:    the elements are joined into a single delimited string
+//	index:   each element becomes its own field, named after its index
+func (c *converter) convertScalarSlice(s reflect.Value, t fieldTags, path []string) error {
+	switch t.slice {
+	case sliceExplode:
+		if implements(s.Type().Elem(), valuerType) {
+			return c.explodeValuers(s, path)
+		}
+
+		name := c.cellName(path)
+		children := make([]*row, s.Len())
+		for i := range children {
+			children[i] = &row{cells: []cell{{name, s.Index(i)}}}
+		}
+		c.row.addGroup(children)
+	case sliceJoin:
+		delim := t.delim
+		if delim == "" {
+			delim = defaultDelim
+		}
+		joined, err := c.joinSlice(s, delim)
+		if err != nil {
+			return err
+		}
+		c.addCell(reflect.ValueOf(joined), path)
+	case sliceIndex:
+		for i := 0; i < s.Len(); i++ {
+			if err := c.handleValue(s.Index(i), fieldTags{}, c.fieldPath(strconv.Itoa(i), fieldTags{}, path)); err != nil {
+				return err
+			}
+		}
+	case "":
+		return c.unsupported(errors.New("unsupported type: converted types may not contain slices"))
+	default:
+		return fmt.Errorf("unsupported slice option %q", t.slice)
+	}
+	return nil
+}
+
+// explodeValuers explodes a slice of FrameValuers. Each element is converted
+// into its own row like the elements of convertSlice, so FrameValue can
+// return anything a field can hold.
+func (c *converter) explodeValuers(s reflect.Value, path []string) error {
+	parent := c.row
+	defer func() { c.row = parent }()
+
+	children := make([]*row, s.Len())
+	for i := range children {
+		c.row = &row{}
+		if err := c.handleValue(s.Index(i), fieldTags{}, path); err != nil {
+			return err
+		}
+		children[i] = c.row
+	}
+
+	parent.addGroup(children)
+	return nil
+}
+
+func (c *converter) joinSlice(s reflect.Value, delim string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < s.Len(); i++ {
+		if i > 0 {
+			b.WriteString(delim)
+		}
+
+		elem := s.Index(i)
+		value, ok, err := frameValue(elem)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			if value == nil {
+				// nil values are joined as empty strings
+				continue
+			}
+			elem = reflect.ValueOf(value)
+		}
+		b.WriteString(formatElem(c.inLocation(fieldValue(elem))))
+	}
+	return b.String(), nil
+}
+
+func formatElem(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch e := v.Interface().(type) {
+	case string:
+		return e
+	case time.Time:
+		return e.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(e)
+	}
+}
+
+// decodeScalarSlice reverses convertScalarSlice. Exploded slices are decoded
+// into a single element for each row.
+func (d *decoder) decodeScalarSlice(v reflect.Value, row int, t fieldTags, path []string) error {
+	fieldName := d.columnName(path)
+	var values []reflect.Value
+	switch t.slice {
+	case sliceExplode:
+		f, ok := d.fields[fieldName]
+		if !ok {
+			return nil
+		}
+		if value := reflect.ValueOf(f.At(row)); !isNull(value) {
+			values = append(values, value)
+		}
+	case sliceJoin:
+		f, ok := d.fields[fieldName]
+		if !ok {
+			return nil
+		}
+		s, ok := f.ConcreteAt(row)
+		if !ok || s.(string) == "" {
+			return nil
+		}
+
+		delim := t.delim
+		if delim == "" {
+			delim = defaultDelim
+		}
+		for _, part := range strings.Split(s.(string), delim) {
+			elem, err := parseElem(part, v.Type().Elem())
+			if err != nil {
+				return fmt.Errorf("unable to decode %s: %w", fieldName, err)
+			}
+			values = append(values, elem)
+		}
+	case sliceIndex:
+		for i := 0; ; i++ {
+			f, ok := d.fields[d.columnName(d.fieldPath(strconv.Itoa(i), fieldTags{}, path))]
+			if !ok || isNull(reflect.ValueOf(f.At(row))) {
+				break
+			}
+			values = append(values, reflect.ValueOf(f.At(row)))
+		}
+	default:
+		return nil
+	}
+
+	s := reflect.MakeSlice(v.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := assign(s.Index(i), value); err != nil {
+			return fmt.Errorf("unable to decode %s: %w", fieldName, err)
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func isNull(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// parseElem reverses formatElem
+func parseElem(s string, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	v := reflect.New(t).Elem()
+	switch {
+	case t.Kind() == reflect.String:
+		v.SetString(s)
+	case t.Kind() == reflect.Interface && reflect.TypeOf(s).Implements(t):
+		v.Set(reflect.ValueOf(s))
+	case fieldType(t) == timeType:
+		tme, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return v, err
+		}
+		v.Set(reflect.ValueOf(tme).Convert(t))
+	default:
+		if _, err := fmt.Sscan(s, v.Addr().Interface()); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,65 @@
+package framestruct
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ToDataFrameFromChan converts the rows received from rows until it's
+// closed. Rows are received one at a time, so producers are blocked until
+// their previous row has been converted. When ctx is done, or a row can't be
+// converted, it returns the frame built so far along with the error.
+func ToDataFrameFromChan[T any](ctx context.Context, name string, rows <-chan T, opts ...Option) (*data.Frame, error) {
+	next := func() (T, bool, error) {
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, false, ctx.Err()
+		case row, ok := <-rows:
+			return row, ok, nil
+		}
+	}
+	return ToDataFrameFromIter(ctx, name, next, opts...)
+}
+
+// ToDataFrameFromIter converts the rows returned by next until it returns
+// false. When ctx is done, or next or a row returns an error, it returns the
+// frame built so far along with the error. With WithMaxRows, the first row
+// past the maximum is taken from next before ErrMaxRows is returned.
+func ToDataFrameFromIter[T any](ctx context.Context, name string, next func() (T, bool, error), opts ...Option) (*data.Frame, error) {
+	b, err := NewFrameBuilder(name, (*T)(nil), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return partialFrame(b, err)
+		}
+
+		row, ok, err := next()
+		if err != nil {
+			return partialFrame(b, err)
+		}
+		if !ok {
+			return b.Frame()
+		}
+
+		// Append returns ErrMaxRows for a row past the maximum, so a
+		// producer with exactly the maximum number of rows succeeds
+		if err := b.Append(row); err != nil {
+			return partialFrame(b, err)
+		}
+	}
+}
+
+// partialFrame returns the frame built so far along with err, which is the
+// reason the frame stopped early
+func partialFrame(b *FrameBuilder, err error) (*data.Frame, error) {
+	frame, frameErr := b.Frame()
+	if frameErr != nil {
+		return nil, frameErr
+	}
+	return frame, err
+}
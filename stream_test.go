@@ -0,0 +1,136 @@
+package framestruct_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToDataFrameFromChan(t *testing.T) {
+	t.Run("it converts every row until the channel is closed", func(t *testing.T) {
+		rows := make(chan simpleStruct)
+		go func() {
+			defer close(rows)
+			rows <- simpleStruct{"foo", 36, "baz"}
+			rows <- simpleStruct{"foo1", 37, "baz1"}
+		}()
+
+		frame, err := framestruct.ToDataFrameFromChan(context.Background(), "results", rows)
+		require.Nil(t, err)
+
+		expected, err := framestruct.ToDataFrame("results", []simpleStruct{
+			{"foo", 36, "baz"},
+			{"foo1", 37, "baz1"},
+		})
+		require.Nil(t, err)
+		require.Equal(t, expected, frame)
+	})
+
+	t.Run("it returns the rows converted before the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		rows := make(chan simpleStruct)
+		go func() {
+			rows <- simpleStruct{"foo", 36, "baz"}
+			cancel()
+		}()
+
+		frame, err := framestruct.ToDataFrameFromChan(ctx, "results", rows)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Equal(t, 1, frame.Fields[0].Len())
+		require.Equal(t, "foo", frame.Fields[0].At(0))
+	})
+
+	t.Run("it stops receiving at the maximum number of rows", func(t *testing.T) {
+		rows := make(chan simpleStruct, 4)
+		rows <- simpleStruct{"foo", 36, "baz"}
+		rows <- simpleStruct{"foo1", 37, "baz1"}
+		rows <- simpleStruct{"foo2", 38, "baz2"}
+		rows <- simpleStruct{"foo3", 39, "baz3"}
+		close(rows)
+
+		frame, err := framestruct.ToDataFrameFromChan(context.Background(), "results", rows, framestruct.WithMaxRows(2))
+		require.True(t, errors.Is(err, framestruct.ErrMaxRows))
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Len(t, rows, 1)
+	})
+
+	t.Run("it converts exactly the maximum number of rows", func(t *testing.T) {
+		rows := make(chan simpleStruct, 2)
+		rows <- simpleStruct{"foo", 36, "baz"}
+		rows <- simpleStruct{"foo1", 37, "baz1"}
+		close(rows)
+
+		frame, err := framestruct.ToDataFrameFromChan(context.Background(), "results", rows, framestruct.WithMaxRows(2))
+		require.Nil(t, err)
+		require.Equal(t, 2, frame.Fields[0].Len())
+
+		i := 0
+		next := func() (simpleStruct, bool, error) {
+			if i == 2 {
+				return simpleStruct{}, false, nil
+			}
+			i++
+			return simpleStruct{"foo", int32(i), "baz"}, true, nil
+		}
+
+		frame, err = framestruct.ToDataFrameFromIter(context.Background(), "results", next, framestruct.WithMaxRows(2))
+		require.Nil(t, err)
+		require.Equal(t, 2, frame.Fields[0].Len())
+	})
+}
+
+func TestToDataFrameFromIter(t *testing.T) {
+	t.Run("it converts every row until next returns false", func(t *testing.T) {
+		strcts := []simpleStruct{
+			{"foo", 36, "baz"},
+			{"foo1", 37, "baz1"},
+		}
+
+		i := 0
+		next := func() (simpleStruct, bool, error) {
+			if i == len(strcts) {
+				return simpleStruct{}, false, nil
+			}
+			i++
+			return strcts[i-1], true, nil
+		}
+
+		frame, err := framestruct.ToDataFrameFromIter(context.Background(), "results", next)
+		require.Nil(t, err)
+
+		expected, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+		require.Equal(t, expected, frame)
+	})
+
+	t.Run("it returns the rows converted before next returns an error", func(t *testing.T) {
+		i := 0
+		next := func() (map[string]interface{}, bool, error) {
+			if i == 2 {
+				return nil, false, errors.New("cursor closed")
+			}
+			i++
+			return map[string]interface{}{"a": int64(i)}, true, nil
+		}
+
+		frame, err := framestruct.ToDataFrameFromIter(context.Background(), "results", next)
+		require.Error(t, err)
+		require.Equal(t, "cursor closed", err.Error())
+		require.Equal(t, 2, frame.Fields[0].Len())
+		require.Equal(t, int64(2), frame.Fields[0].At(1))
+	})
+
+	t.Run("it returns an error for unsupported types", func(t *testing.T) {
+		next := func() ([]string, bool, error) {
+			return []string{"foo"}, true, nil
+		}
+
+		frame, err := framestruct.ToDataFrameFromIter(context.Background(), "results", next)
+		require.Error(t, err)
+		require.Nil(t, frame)
+	})
+}
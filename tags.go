@@ -0,0 +1,173 @@
+package framestruct
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type fieldTags struct {
+	name       string
+	omitParent bool
+	col0       bool
+	asString   bool
+	label      bool
+	value      bool
+	slice      string
+	delim      string
+	order      int
+	ordered    bool
+
+	// field config
+	unit        string
+	displayName string
+	decimals    string
+	min         string
+	max         string
+}
+
+// parseTags parses a frame tag: a name followed by comma separated options
+// in any order. Options are flags, like omitparent, or key=value pairs. The
+// name and values can be quoted with single quotes to include commas, e.g.
+// displayName='Latency, ms'. Two single quotes are a literal quote.
+//
+// Tags used to be positional: name,omitparent,col0. For compatibility, an
+// unknown flag in the third position means col0.
+func parseTags(s string) (fieldTags, error) {
+	var tags fieldTags
+
+	name, s, more := cut(s, ',')
+	name, err := unquote(strings.TrimSpace(name))
+	if err != nil {
+		return tags, err
+	}
+	tags.name = name
+
+	for i := 1; more; i++ {
+		var option string
+		option, s, more = cut(s, ',')
+		option = strings.TrimSpace(option)
+
+		key, value, isPair := cut(option, '=')
+		switch {
+		case option == "":
+			// an empty slot of a positional tag
+		case isPair:
+			value, err := unquote(strings.TrimSpace(value))
+			if err != nil {
+				return tags, err
+			}
+			if err := tags.setOption(strings.TrimSpace(key), value); err != nil {
+				return tags, err
+			}
+		case tags.setFlag(option):
+		case i == 2:
+			tags.col0 = true
+		default:
+			return tags, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	return tags, nil
+}
+
+func (t *fieldTags) setFlag(flag string) bool {
+	switch flag {
+	case "omitparent":
+		t.omitParent = true
+	case "col0":
+		t.col0 = true
+	case "string":
+		t.asString = true
+	case "label":
+		t.label = true
+	case "value":
+		t.value = true
+	default:
+		return false
+	}
+	return true
+}
+
+func (t *fieldTags) setOption(key, value string) error {
+	switch key {
+	case "slice":
+		t.slice = value
+	case "delim":
+		t.delim = value
+	case "order":
+		order, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("order must be an integer: %q", value)
+		}
+		t.order, t.ordered = order, true
+	case "unit":
+		t.unit = value
+	case "displayName":
+		t.displayName = value
+	case "decimals":
+		t.decimals = value
+	case "min":
+		t.min = value
+	case "max":
+		t.max = value
+	case "":
+		return errors.New("option is missing a name")
+	default:
+		return fmt.Errorf("unknown option %q", key)
+	}
+	return nil
+}
+
+// cut slices s around the first sep that isn't quoted. Only a quote at the
+// start of a name or value begins a quoted string, so apostrophes like the
+// one in it's are literal.
+func cut(s string, sep byte) (before, after string, found bool) {
+	quoted, start := false, true
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			// an escaped quote
+			i++
+		case quoted && s[i] == '\'':
+			quoted = false
+		case quoted:
+		case s[i] == '\'' && start:
+			quoted = true
+		case s[i] == sep:
+			return s[:i], s[i+1:], true
+		}
+
+		switch s[i] {
+		case ',', '=':
+			start = true
+		case ' ':
+		default:
+			start = false
+		}
+	}
+	return s, "", false
+}
+
+// unquote returns the contents of s if it's quoted, or s if it isn't
+func unquote(s string) (string, error) {
+	if !strings.HasPrefix(s, "'") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] != '\'':
+			b.WriteByte(s[i])
+		case i+1 < len(s) && s[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case i+1 < len(s):
+			return "", fmt.Errorf("unexpected %q after quote in %q", s[i+1:], s)
+		default:
+			return b.String(), nil
+		}
+	}
+	return "", fmt.Errorf("unterminated quote in %q", s)
+}
@@ -0,0 +1,124 @@
+package framestruct
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// textValue returns the string form of v when it should be stored as a
+// string: always when asString is set, otherwise only when the converter has
+// a string fallback and v's type can't be stored on its own. Pointers are
+// stored as nullable strings. ok is false when v isn't stored as a string.
+func (c *converter) textValue(v reflect.Value, asString bool) (text reflect.Value, ok bool, err error) {
+	if !c.storesText(v.Type(), asString) {
+		return v, false, nil
+	}
+
+	if isNull(v) {
+		return reflect.Zero(reflect.TypeOf((*string)(nil))), true, nil
+	}
+
+	var s string
+	if m, ok := implementation(v, textMarshalerType); ok {
+		b, err := m.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return v, true, err
+		}
+		s = string(b)
+	} else if str, ok := implementation(v, stringerType); ok {
+		s = str.Interface().(fmt.Stringer).String()
+	} else {
+		s = formatElem(v)
+	}
+
+	if v.Kind() == reflect.Ptr {
+		return reflect.ValueOf(&s), true, nil
+	}
+	return reflect.ValueOf(s), true, nil
+}
+
+func (c *converter) useStringFallback(t reflect.Type) bool {
+	if !c.stringFallback || fieldType(derefType(t)) != nil {
+		return false
+	}
+
+	return implements(t, textMarshalerType) || implements(t, stringerType)
+}
+
+// implements reports whether t or a pointer to t implements iface
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// storesText reports whether values of type t are stored as strings by
+// textValue
+func (c *converter) storesText(t reflect.Type, asString bool) bool {
+	if c.useStringFallback(t) {
+		return true
+	}
+
+	return asString && (implements(t, textMarshalerType) ||
+		implements(t, stringerType) ||
+		fieldType(derefType(t)) != nil)
+}
+
+// decodeText reverses textValue using encoding.TextUnmarshaler or by parsing
+// the string. Values of types that only implement fmt.Stringer can't be
+// decoded and are left as they are.
+func (d *decoder) decodeText(v reflect.Value, row int, fieldName string) error {
+	f, ok := d.fields[fieldName]
+	if !ok {
+		return nil
+	}
+
+	src, ok := f.ConcreteAt(row)
+	if !ok {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("unable to decode %s: %T is not a string", fieldName, src)
+	}
+
+	if err := setText(v, s); err != nil {
+		return fmt.Errorf("unable to decode %s: %w", fieldName, err)
+	}
+	return nil
+}
+
+// setText sets v to the value s is the text of
+func setText(v reflect.Value, s string) error {
+	switch {
+	case implements(v.Type(), textUnmarshalerType):
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		u, _ := implementation(v, textUnmarshalerType)
+		return u.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	case implements(v.Type(), stringerType):
+		return nil
+	}
+
+	elem, err := parseElem(s, v.Type())
+	if err != nil {
+		return err
+	}
+	return assign(v, elem)
+}
@@ -0,0 +1,123 @@
+package framestruct_test
+
+import (
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringFallback(t *testing.T) {
+	t.Run("it returns an error for text marshalers without the fallback", func(t *testing.T) {
+		_, err := framestruct.ToDataFrame("results", host{Name: "foo", IP: net.ParseIP("10.0.0.1")})
+		require.Error(t, err)
+	})
+
+	t.Run("it converts text marshalers and stringers to strings", func(t *testing.T) {
+		strct := host{
+			Name:  "foo",
+			IP:    net.ParseIP("10.0.0.1"),
+			Count: big.NewInt(42),
+			Level: warn,
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct, framestruct.WithStringFallback())
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 4)
+		require.Equal(t, "IP", frame.Fields[1].Name)
+		require.Equal(t, "10.0.0.1", frame.Fields[1].At(0))
+		require.Equal(t, "Count", frame.Fields[2].Name)
+		require.Equal(t, "42", *frame.Fields[2].At(0).(*string))
+		require.Equal(t, "Level", frame.Fields[3].Name)
+		require.Equal(t, int64(2), frame.Fields[3].At(0))
+	})
+
+	t.Run("it treats nil pointers as null", func(t *testing.T) {
+		strcts := []host{
+			{Name: "foo", IP: net.ParseIP("10.0.0.1"), Count: big.NewInt(42)},
+			{Name: "bar", IP: net.ParseIP("10.0.0.2")},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts, framestruct.WithStringFallback())
+		require.Nil(t, err)
+
+		require.Equal(t, "42", *frame.Fields[2].At(0).(*string))
+		require.Nil(t, frame.Fields[2].At(1))
+	})
+
+	t.Run("it converts fields with the string tag option", func(t *testing.T) {
+		strct := stringTags{Level: warn, Count: 36, Ok: true}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, "level", frame.Fields[0].Name)
+		require.Equal(t, "warn", frame.Fields[0].At(0))
+		require.Equal(t, "36", frame.Fields[1].At(0))
+		require.Equal(t, "true", frame.Fields[2].At(0))
+	})
+
+	t.Run("it decodes strings with text unmarshalers", func(t *testing.T) {
+		strct := host{
+			Name:  "foo",
+			IP:    net.ParseIP("10.0.0.1"),
+			Count: big.NewInt(42),
+			Level: warn,
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct, framestruct.WithStringFallback())
+		require.Nil(t, err)
+
+		var out host
+		err = framestruct.FromDataFrame(frame, &out, framestruct.WithStringFallback())
+		require.Nil(t, err)
+		require.Equal(t, strct, out)
+	})
+
+	t.Run("it decodes fields with the string tag option", func(t *testing.T) {
+		strct := stringTags{Level: warn, Count: 36, Ok: true}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		var out stringTags
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, stringTags{Count: 36, Ok: true}, out)
+	})
+}
+
+type level int
+
+const (
+	info level = iota + 1
+	warn
+)
+
+func (l level) String() string {
+	switch l {
+	case info:
+		return "info"
+	case warn:
+		return "warn"
+	default:
+		return "unknown"
+	}
+}
+
+type host struct {
+	Name  string
+	IP    net.IP
+	Count *big.Int
+	Level level
+}
+
+type stringTags struct {
+	Level level `frame:"level,string"`
+	Count int   `frame:",string"`
+	Ok    bool  `frame:",omitparent,string"`
+}
@@ -0,0 +1,131 @@
+package framestruct
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ErrDuplicateTimes is returned by WithTimeSort(RejectDuplicateTimes) when
+// more than one row has the same time
+var ErrDuplicateTimes = errors.New("frame has more than one row with the same time")
+
+// DuplicateTimes controls what WithTimeSort does when more than one row has
+// the same time
+type DuplicateTimes int
+
+const (
+	// AllowDuplicateTimes keeps rows with the same time in the order they
+	// were converted
+	AllowDuplicateTimes DuplicateTimes = iota
+
+	// NoticeDuplicateTimes adds a warning notice to the frame's metadata
+	NoticeDuplicateTimes
+
+	// RejectDuplicateTimes returns ErrDuplicateTimes
+	RejectDuplicateTimes
+)
+
+// WithTimeSort makes the time field the 0th column of the frame and sorts
+// rows by it, as Grafana expects of time series. The time field is the col0
+// field if it's a time, otherwise the first time field. Rows with the same
+// time keep their order and rows with a null time go last. Frames without a
+// time field are unchanged.
+func WithTimeSort(dups DuplicateTimes) Option {
+	return func(c *converter) {
+		c.timeSort = true
+		c.duplicateTimes = dups
+	}
+}
+
+// timeField returns the name of the field WithTimeSort sorts by, or "" if
+// there isn't one
+func (c *converter) timeField() string {
+	if col, ok := c.fields[c.col0]; ok && col.field.Type().Time() {
+		return c.col0
+	}
+	for _, name := range c.fieldNames {
+		if c.fields[name].field.Type().Time() {
+			return name
+		}
+	}
+	return ""
+}
+
+// sortByTime sorts the rows of frame by its 0th field, which is a time
+func (c *converter) sortByTime(frame *data.Frame) error {
+	times := timesOf(frame.Fields[0])
+	rows := make([]int, len(times))
+	for i := range rows {
+		rows[i] = i
+	}
+
+	less := func(i, j int) bool {
+		a, b := times[rows[i]], times[rows[j]]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	}
+	if !sort.SliceIsSorted(rows, less) {
+		sort.SliceStable(rows, less)
+		for i, f := range frame.Fields {
+			frame.Fields[i] = reorder(f, rows)
+		}
+	}
+
+	if c.duplicateTimes == AllowDuplicateTimes {
+		return nil
+	}
+	for i := 1; i < len(rows); i++ {
+		a, b := times[rows[i-1]], times[rows[i]]
+		if a == nil || b == nil || !a.Equal(*b) {
+			continue
+		}
+
+		at := b.Format(time.RFC3339Nano)
+		if c.duplicateTimes == RejectDuplicateTimes {
+			return fmt.Errorf("%w: %s", ErrDuplicateTimes, at)
+		}
+
+		notice := data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("%s has more than one row at %s", frame.Fields[0].Name, at),
+		}
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		// the notices may belong to a FrameMetaProvider
+		notices := frame.Meta.Notices
+		frame.Meta.Notices = append(notices[:len(notices):len(notices)], notice)
+		return nil
+	}
+	return nil
+}
+
+// timesOf returns the values of a time field, with nil for nulls
+func timesOf(f *data.Field) []*time.Time {
+	times := make([]*time.Time, f.Len())
+	for i := range times {
+		if v, ok := f.ConcreteAt(i); ok {
+			t := v.(time.Time)
+			times[i] = &t
+		}
+	}
+	return times
+}
+
+// reorder returns a copy of f whose ith value is the value of f at rows[i]
+func reorder(f *data.Field, rows []int) *data.Field {
+	sorted := data.NewFieldFromFieldType(f.Type(), len(rows))
+	sorted.Name = f.Name
+	sorted.Labels = f.Labels
+	sorted.Config = f.Config
+	for i, row := range rows {
+		sorted.Set(i, f.At(row))
+	}
+	return sorted
+}
@@ -0,0 +1,122 @@
+package framestruct_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeSort(t *testing.T) {
+	tme := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+
+	t.Run("it moves the first time field to column 0 and sorts rows by it", func(t *testing.T) {
+		rows := []timedRow{
+			{"c", tme.Add(2 * time.Minute), tme},
+			{"a", tme, tme.Add(time.Minute)},
+			{"b", tme.Add(time.Minute), tme.Add(2 * time.Minute)},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows, framestruct.WithTimeSort(framestruct.AllowDuplicateTimes))
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Start", "Name", "End"}, fieldNames(frame))
+		require.Equal(t, tme, frame.Fields[0].At(0))
+		require.Equal(t, "a", frame.Fields[1].At(0))
+		require.Equal(t, "b", frame.Fields[1].At(1))
+		require.Equal(t, "c", frame.Fields[1].At(2))
+		require.Equal(t, tme, frame.Fields[2].At(2))
+	})
+
+	t.Run("it sorts by the col0 field when it's a time", func(t *testing.T) {
+		rows := []timedCol0{
+			{tme, tme.Add(time.Minute)},
+			{tme.Add(time.Minute), tme},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows, framestruct.WithTimeSort(framestruct.AllowDuplicateTimes))
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"End", "Start"}, fieldNames(frame))
+		require.Equal(t, tme, frame.Fields[0].At(0))
+		require.Equal(t, tme.Add(time.Minute), frame.Fields[1].At(0))
+	})
+
+	t.Run("it keeps the order of rows with the same time and puts null times last", func(t *testing.T) {
+		later := tme.Add(time.Minute)
+		rows := []nullableTime{
+			{"a", nil},
+			{"b", &later},
+			{"c", &tme},
+			{"d", &later},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows, framestruct.WithTimeSort(framestruct.AllowDuplicateTimes))
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Time", "Name"}, fieldNames(frame))
+		for i, name := range []string{"c", "b", "d", "a"} {
+			require.Equal(t, name, frame.Fields[1].At(i))
+		}
+		require.Nil(t, frame.Fields[0].At(3))
+		require.Nil(t, frame.Meta)
+	})
+
+	t.Run("it leaves frames without a time field unchanged", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", simpleStruct{"foo", 36, "baz"}, framestruct.WithTimeSort(framestruct.RejectDuplicateTimes))
+		require.Nil(t, err)
+		require.Equal(t, []string{"Thing1", "Thing2", "Thing3"}, fieldNames(frame))
+	})
+
+	t.Run("it adds a notice when rows have the same time", func(t *testing.T) {
+		rows := []timedRow{
+			{"a", tme, tme},
+			{"b", tme, tme},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", rows, framestruct.WithTimeSort(framestruct.NoticeDuplicateTimes))
+		require.Nil(t, err)
+
+		require.Equal(t, []data.Notice{{
+			Severity: data.NoticeSeverityWarning,
+			Text:     "Start has more than one row at 2009-11-17T20:34:58Z",
+		}}, frame.Meta.Notices)
+	})
+
+	t.Run("it returns an error when rows have the same time", func(t *testing.T) {
+		rows := []timedRow{
+			{"a", tme, tme},
+			{"b", tme, tme},
+		}
+
+		_, err := framestruct.ToDataFrame("results", rows, framestruct.WithTimeSort(framestruct.RejectDuplicateTimes))
+		require.True(t, errors.Is(err, framestruct.ErrDuplicateTimes))
+
+		builder, err := framestruct.NewFrameBuilder("results", timedRow{}, framestruct.WithTimeSort(framestruct.RejectDuplicateTimes))
+		require.Nil(t, err)
+		for _, row := range rows {
+			require.Nil(t, builder.Append(row))
+		}
+		_, err = builder.Frame()
+		require.True(t, errors.Is(err, framestruct.ErrDuplicateTimes))
+	})
+}
+
+type timedRow struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+type timedCol0 struct {
+	Start time.Time
+	End   time.Time `frame:"End,col0"`
+}
+
+type nullableTime struct {
+	Name string
+	Time *time.Time
+}
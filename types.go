@@ -1,75 +1,88 @@
 package framestruct
 
 import (
-	"fmt"
 	"reflect"
+	"sync"
 	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
-func sliceFor(value interface{}) (interface{}, error) {
-	switch v := value.(type) {
-	case int8:
-		return []int8{}, nil
-	case *int8:
-		return []*int8{}, nil
-	case int16:
-		return []int16{}, nil
-	case *int16:
-		return []*int16{}, nil
-	case int32:
-		return []int32{}, nil
-	case *int32:
-		return []*int32{}, nil
-	case int64:
-		return []int64{}, nil
-	case *int64:
-		return []*int64{}, nil
-	case uint8:
-		return []uint8{}, nil
-	case *uint8:
-		return []*uint8{}, nil
-	case uint16:
-		return []uint16{}, nil
-	case *uint16:
-		return []*uint16{}, nil
-	case uint32:
-		return []uint32{}, nil
-	case *uint32:
-		return []*uint32{}, nil
-	case uint64:
-		return []uint64{}, nil
-	case *uint64:
-		return []*uint64{}, nil
-	case float32:
-		return []float32{}, nil
-	case *float32:
-		return []*float32{}, nil
-	case float64:
-		return []float64{}, nil
-	case *float64:
-		return []*float64{}, nil
-	case string:
-		return []string{}, nil
-	case *string:
-		return []*string{}, nil
-	case bool:
-		return []bool{}, nil
-	case *bool:
-		return []*bool{}, nil
-	case time.Time:
-		return []time.Time{}, nil
-	case *time.Time:
-		return []*time.Time{}, nil
-	default:
-		return nil, fmt.Errorf("unsupported type %T", v)
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldType returns the type of the values stored in a data.Field for values
+// of type t, or nil if t can't be stored. Named types are stored as their
+// underlying type, and int and uint are widened to 64 bits.
+func fieldType(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Int8:
+		return reflect.TypeOf(int8(0))
+	case reflect.Int16:
+		return reflect.TypeOf(int16(0))
+	case reflect.Int32:
+		return reflect.TypeOf(int32(0))
+	case reflect.Int, reflect.Int64:
+		return reflect.TypeOf(int64(0))
+	case reflect.Uint8:
+		return reflect.TypeOf(uint8(0))
+	case reflect.Uint16:
+		return reflect.TypeOf(uint16(0))
+	case reflect.Uint32:
+		return reflect.TypeOf(uint32(0))
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return reflect.TypeOf(uint64(0))
+	case reflect.Float32:
+		return reflect.TypeOf(float32(0))
+	case reflect.Float64:
+		return reflect.TypeOf(float64(0))
+	case reflect.String:
+		return reflect.TypeOf("")
+	case reflect.Bool:
+		return reflect.TypeOf(false)
+	case reflect.Struct:
+		if t.ConvertibleTo(timeType) {
+			return timeType
+		}
+	}
+	return nil
+}
+
+// fieldValue converts v, or the value v points to, to its fieldType
+func fieldValue(v reflect.Value) reflect.Value {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	ft := fieldType(t)
+	if ft == nil || ft == t {
+		return v
+	}
+
+	if v.Kind() != reflect.Ptr {
+		return v.Convert(ft)
 	}
+
+	if v.IsNil() {
+		return reflect.Zero(reflect.PtrTo(ft))
+	}
+	p := reflect.New(ft)
+	p.Elem().Set(v.Elem().Convert(ft))
+	return p
 }
 
 func supportedToplevelType(v reflect.Value) bool {
 	switch v.Kind() {
 	case reflect.Slice:
+		if isScalar(v.Type().Elem()) {
+			return true
+		}
 		for i := 0; i < v.Len(); i++ {
-			s := v.Index(i)
+			s := indirect(v.Index(i))
+			if !s.IsValid() {
+				// nil elements are skipped or null
+				continue
+			}
 			return supportedToplevelType(s)
 		}
 		return true
@@ -83,3 +96,74 @@ func supportedToplevelType(v reflect.Value) bool {
 		return v.Kind() == reflect.Map
 	}
 }
+
+// supportedRowType reports whether values of type t can be converted into a
+// row
+func supportedRowType(t reflect.Type) bool {
+	return isNestedStruct(t) || t.Kind() == reflect.Map || isScalar(t) || implements(t, valuerType)
+}
+
+// indirect returns the value v points to through any number of pointers
+// and interfaces, or the zero Value if any of them are nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// indirectType returns the type values of type t point to through any
+// number of pointers
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isScalar reports whether values of type t, like float64 or *time.Time, are
+// stored in a single column
+func isScalar(t reflect.Type) bool {
+	return fieldType(derefType(t)) != nil
+}
+
+var fieldTypes sync.Map // map[reflect.Type]data.FieldType
+
+func fieldTypeFor(v reflect.Value) data.FieldType {
+	t := v.Type()
+	if ft, ok := fieldTypes.Load(t); ok {
+		return ft.(data.FieldType)
+	}
+
+	var ft data.FieldType
+	if t.Kind() == reflect.Ptr {
+		ft = data.FieldTypeFor(reflect.Zero(t.Elem()).Interface()).NullableType()
+	} else {
+		ft = data.FieldTypeFor(reflect.Zero(t).Interface())
+	}
+
+	fieldTypes.Store(t, ft)
+	return ft
+}
+
+// inLocation converts times to the converter's location, if it has one
+func (c *converter) inLocation(v reflect.Value) reflect.Value {
+	if c.location == nil {
+		return v
+	}
+
+	switch t := v.Interface().(type) {
+	case time.Time:
+		return reflect.ValueOf(t.In(c.location))
+	case *time.Time:
+		if t == nil {
+			return v
+		}
+		local := t.In(c.location)
+		return reflect.ValueOf(&local)
+	}
+	return v
+}
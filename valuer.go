@@ -0,0 +1,89 @@
+package framestruct
+
+import "reflect"
+
+// FrameValuer is implemented by types that control how they are converted
+// to a data.Frame. FrameValue returns the value to convert in their place,
+// e.g. a Money type could return its amount as a float64 or a net.IP could
+// return its string form. The returned value can be anything ToDataFrame can
+// convert, including structs and maps. Returning nil makes the value null.
+type FrameValuer interface {
+	FrameValue() (interface{}, error)
+}
+
+// FrameScanner is implemented by types that control how they are populated
+// by FromDataFrame. ScanFrameValue is called with the value of the type's
+// column, or nil if the value is null.
+type FrameScanner interface {
+	ScanFrameValue(src interface{}) error
+}
+
+var (
+	valuerType  = reflect.TypeOf((*FrameValuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*FrameScanner)(nil)).Elem()
+)
+
+// frameValue returns the result of v's FrameValue method. ok is false when v
+// doesn't implement FrameValuer.
+func frameValue(v reflect.Value) (value interface{}, ok bool, err error) {
+	v, ok = implementation(v, valuerType)
+	if !ok {
+		return nil, false, nil
+	}
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, true, nil
+	}
+
+	value, err = v.Interface().(FrameValuer).FrameValue()
+	return value, true, err
+}
+
+// implementation returns v, or v's address when methods have pointer
+// receivers, if it implements iface
+func implementation(v reflect.Value, iface reflect.Type) (reflect.Value, bool) {
+	if !v.CanInterface() {
+		return v, false
+	}
+
+	if v.Type().Implements(iface) {
+		return v, true
+	}
+
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(iface) {
+		return v.Addr(), true
+	}
+	return v, false
+}
+
+func isScanner(t reflect.Type) bool {
+	return implements(t, scannerType)
+}
+
+// decodeScanner populates a FrameScanner from the named column
+func (d *decoder) decodeScanner(v reflect.Value, row int, fieldName string) error {
+	f, ok := d.fields[fieldName]
+	if !ok {
+		return nil
+	}
+
+	src, ok := f.ConcreteAt(row)
+	if !ok {
+		src = nil
+	}
+
+	switch {
+	case v.Kind() == reflect.Ptr && v.Type().Implements(scannerType):
+		if src == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+	case !v.Type().Implements(scannerType):
+		v = v.Addr()
+	}
+
+	return v.Interface().(FrameScanner).ScanFrameValue(src)
+}
@@ -0,0 +1,225 @@
+package framestruct_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/masslessparticle/go-framestruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameValuer(t *testing.T) {
+	t.Run("it converts the value returned by FrameValue", func(t *testing.T) {
+		strct := order{"foo", money{1050}, &money{99}}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, "Price", frame.Fields[1].Name)
+		require.Equal(t, 10.5, frame.Fields[1].At(0))
+		require.Equal(t, "Discount", frame.Fields[2].Name)
+		require.Equal(t, 0.99, frame.Fields[2].At(0))
+	})
+
+	t.Run("it uses pointer receivers", func(t *testing.T) {
+		strct := []pointerValuers{{region{"us", 1}}, {region{"eu", 2}}}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, "Region", frame.Fields[0].Name)
+		require.Equal(t, "us-1", frame.Fields[0].At(0))
+		require.Equal(t, "eu-2", frame.Fields[0].At(1))
+	})
+
+	t.Run("it flattens structs returned by FrameValue", func(t *testing.T) {
+		strct := wrapsStruct{flattened{}}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, "Foo.Thing7", frame.Fields[0].Name)
+		require.Equal(t, "Foo.Thing8", frame.Fields[1].Name)
+	})
+
+	t.Run("it treats nil values as null", func(t *testing.T) {
+		strcts := []order{
+			{"foo", money{1050}, &money{99}},
+			{"bar", money{1050}, nil},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 0.99, *frame.Fields[2].At(0).(*float64))
+		require.Nil(t, frame.Fields[2].At(1))
+	})
+
+	t.Run("it converts slices of FrameValuers into values", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", []money{{1050}, {99}})
+		require.Nil(t, err)
+		require.Equal(t, []string{"results"}, fieldNames(frame))
+		require.Equal(t, 10.5, frame.Fields[0].At(0))
+		require.Equal(t, 0.99, frame.Fields[0].At(1))
+
+		frame, err = framestruct.Convert("results", []money{{1050}, {99}})
+		require.Nil(t, err)
+		require.Equal(t, []string{"results"}, fieldNames(frame))
+		require.Equal(t, 0.99, frame.Fields[0].At(1))
+
+		b, err := framestruct.NewFrameBuilder("results", money{})
+		require.Nil(t, err)
+		require.Nil(t, b.Append(money{1050}))
+		frame, err = b.Frame()
+		require.Nil(t, err)
+		require.Equal(t, []string{"results"}, fieldNames(frame))
+		require.Equal(t, 10.5, frame.Fields[0].At(0))
+	})
+
+	t.Run("it converts nested slices of FrameValuers into values", func(t *testing.T) {
+		strct := basket{
+			Item:     "foo",
+			Prices:   []money{{1050}, {99}},
+			Totals:   []money{{1050}, {99}},
+			Discount: []*money{{25}, nil},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strct)
+		require.Nil(t, err)
+
+		// nil values don't have a column of their own
+		require.Equal(t, []string{"Item", "Prices", "Totals", "Discount.0"}, fieldNames(frame))
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, 10.5, frame.Fields[1].At(0))
+		require.Equal(t, 0.99, frame.Fields[1].At(1))
+		require.Equal(t, "10.5|0.99", frame.Fields[2].At(0))
+		require.Equal(t, 0.25, frame.Fields[3].At(0))
+	})
+
+	t.Run("it converts FrameValuers that return themselves like other values", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", selfValuers{health("ok")})
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"Status"}, fieldNames(frame))
+		require.Equal(t, "ok", frame.Fields[0].At(0))
+	})
+
+	t.Run("it names top level FrameValuers after the frame", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", money{100})
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"results"}, fieldNames(frame))
+		require.Equal(t, 1.0, frame.Fields[0].At(0))
+	})
+
+	t.Run("it returns errors from FrameValue", func(t *testing.T) {
+		_, err := framestruct.ToDataFrame("results", failingValuer{})
+		require.Error(t, err)
+		require.Equal(t, "no value", err.Error())
+	})
+
+	t.Run("it populates FrameScanners", func(t *testing.T) {
+		strcts := []order{
+			{"foo", money{1050}, &money{99}},
+			{"bar", money{1050}, nil},
+		}
+
+		frame, err := framestruct.ToDataFrame("results", strcts)
+		require.Nil(t, err)
+
+		var out []order
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Nil(t, err)
+		require.Equal(t, strcts, out)
+	})
+
+	t.Run("it returns errors from ScanFrameValue", func(t *testing.T) {
+		frame, err := framestruct.ToDataFrame("results", []pointerValuers{{region{"us", 1}}})
+		require.Nil(t, err)
+
+		var out []pointerValuers
+		err = framestruct.FromDataFrame(frame, &out)
+		require.Error(t, err)
+		require.Equal(t, "can't scan regions", err.Error())
+	})
+}
+
+type money struct {
+	cents int64
+}
+
+func (m money) FrameValue() (interface{}, error) {
+	return float64(m.cents) / 100, nil
+}
+
+func (m *money) ScanFrameValue(src interface{}) error {
+	f, ok := src.(float64)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", src)
+	}
+	m.cents = int64(f*100 + 0.5)
+	return nil
+}
+
+type order struct {
+	Item     string
+	Price    money
+	Discount *money
+}
+
+type basket struct {
+	Item     string
+	Prices   []money  `frame:",slice=explode"`
+	Totals   []money  `frame:",slice=join,delim=|"`
+	Discount []*money `frame:",slice=index"`
+}
+
+type health string
+
+func (s health) FrameValue() (interface{}, error) {
+	return s, nil
+}
+
+type selfValuers struct {
+	Status health
+}
+
+type region struct {
+	name string
+	id   int
+}
+
+func (r *region) FrameValue() (interface{}, error) {
+	return fmt.Sprintf("%s-%d", r.name, r.id), nil
+}
+
+func (r *region) ScanFrameValue(src interface{}) error {
+	return errors.New("can't scan regions")
+}
+
+type pointerValuers struct {
+	Region region
+}
+
+type flattened struct{}
+
+func (flattened) FrameValue() (interface{}, error) {
+	return nested3{true, 100}, nil
+}
+
+type wrapsStruct struct {
+	Foo flattened
+}
+
+type failingValuer struct {
+	Foo string
+}
+
+func (failingValuer) FrameValue() (interface{}, error) {
+	return nil, errors.New("no value")
+}